@@ -0,0 +1,20 @@
+// Package cache provides a small key/value cache with an in-memory LRU
+// implementation and a Redis compatible backend.
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache stores byte values by key with a time to live.
+type Cache interface {
+	// Get returns the value stored for key and whether it was found.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set stores value for key. A ttl of zero keeps the value until it is evicted.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete removes key from the cache.
+	Delete(ctx context.Context, key string) error
+}
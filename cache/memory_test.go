@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheGetSet(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(10)
+
+	if _, found, _ := c.Get(ctx, "missing"); found {
+		t.Error("Get() found a key that was never set")
+	}
+
+	if err := c.Set(ctx, "key", []byte("value"), 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	value, found, err := c.Get(ctx, "key")
+	if err != nil || !found || string(value) != "value" {
+		t.Errorf("Get() = %q, %t, %v, want \"value\", true, nil", value, found, err)
+	}
+
+	_ = c.Set(ctx, "key", []byte("updated"), 0)
+	if value, _, _ := c.Get(ctx, "key"); string(value) != "updated" {
+		t.Errorf("Get() = %q after update, want \"updated\"", value)
+	}
+
+	_ = c.Delete(ctx, "key")
+	if _, found, _ := c.Get(ctx, "key"); found {
+		t.Error("Get() found a deleted key")
+	}
+}
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(2)
+
+	_ = c.Set(ctx, "a", []byte("1"), 0)
+	_ = c.Set(ctx, "b", []byte("2"), 0)
+	// reading a makes b the least recently used entry
+	_, _, _ = c.Get(ctx, "a")
+	_ = c.Set(ctx, "c", []byte("3"), 0)
+
+	if _, found, _ := c.Get(ctx, "b"); found {
+		t.Error("b was not evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, found, _ := c.Get(ctx, key); !found {
+			t.Errorf("%s was evicted", key)
+		}
+	}
+}
+
+func TestMemoryCacheExpiresEntries(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(10)
+
+	_ = c.Set(ctx, "short", []byte("1"), 10*time.Millisecond)
+	_ = c.Set(ctx, "forever", []byte("2"), 0)
+
+	if _, found, _ := c.Get(ctx, "short"); !found {
+		t.Fatal("short was not found before it expired")
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	if _, found, _ := c.Get(ctx, "short"); found {
+		t.Error("short was found after it expired")
+	}
+	if _, found, _ := c.Get(ctx, "forever"); !found {
+		t.Error("an entry without ttl expired")
+	}
+}
+
+func TestMemoryCacheSetResetsExpiry(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(10)
+
+	_ = c.Set(ctx, "key", []byte("1"), 10*time.Millisecond)
+	_ = c.Set(ctx, "key", []byte("2"), 0)
+	time.Sleep(20 * time.Millisecond)
+
+	if value, found, _ := c.Get(ctx, "key"); !found || string(value) != "2" {
+		t.Errorf("Get() = %q, %t, want \"2\", true", value, found)
+	}
+}
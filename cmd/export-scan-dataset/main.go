@@ -0,0 +1,265 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/config"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+	helper "github.com/rizkirmdhnnn/sweetlife-backend-go/helpers"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+)
+
+// dataset is a COCO-like export of confirmed scans. There are no bounding boxes,
+// annotations carry the confirmed food, unit count and portion weight of an image.
+type dataset struct {
+	Info        datasetInfo         `json:"info"`
+	Images      []datasetImage      `json:"images"`
+	Categories  []datasetCategory   `json:"categories"`
+	Annotations []datasetAnnotation `json:"annotations"`
+	Predictions []datasetAnnotation `json:"predictions"`
+}
+
+type datasetInfo struct {
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"date_created"`
+}
+
+type datasetImage struct {
+	ID        uint      `json:"id"`
+	URL       string    `json:"coco_url"`
+	FileName  string    `json:"file_name"`
+	ScannedAt time.Time `json:"date_captured"`
+}
+
+type datasetCategory struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Label string `json:"label"`
+}
+
+type datasetAnnotation struct {
+	ID         int      `json:"id"`
+	ImageID    uint     `json:"image_id"`
+	CategoryID int      `json:"category_id"`
+	Unit       int      `json:"unit"`
+	Weight     *float64 `json:"weight,omitempty"`
+	Detected   *bool    `json:"detected,omitempty"`
+}
+
+func main() {
+	format := flag.String("format", "json", "output format, json or csv")
+	out := flag.String("out", "", "output file, stdout when empty")
+	since := flag.String("since", "", "only export scans saved on or after this date (YYYY-MM-DD)")
+	flag.Parse()
+
+	if *format != "json" && *format != "csv" {
+		log.Fatal("Invalid format, use json or csv")
+	}
+
+	// Load environment variables and database
+	config.LoadEnv()
+	config.LoadDatabase()
+
+	// Only scans the user saved have confirmed labels
+	query := config.DB.Where("saved_at IS NOT NULL AND confirmed_items IS NOT NULL AND status = ?", models.ScanStatusDone).Order("id")
+	if *since != "" {
+		date, err := helper.ParsedDate(*since)
+		if err != nil {
+			log.Fatal("Invalid since date, use YYYY-MM-DD")
+		}
+		query = query.Where("saved_at >= ?", date)
+	}
+
+	var sessions []models.ScanSession
+	if err := query.Find(&sessions).Error; err != nil {
+		log.Fatal("Failed to get scan sessions:", err)
+	}
+
+	labels, names, err := loadLabels()
+	if err != nil {
+		log.Fatal("Failed to get food labels:", err)
+	}
+
+	data := buildDataset(sessions, labels, names)
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			log.Fatal("Failed to create output file:", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if *format == "csv" {
+		err = writeCSV(w, data)
+	} else {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(data)
+	}
+	if err != nil {
+		log.Fatal("Failed to write dataset:", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d images with %d annotations\n", len(data.Images), len(data.Annotations))
+}
+
+// loadLabels maps lower case food names to the Indonesian label the ML service uses,
+// and lower case labels back to the food name
+func loadLabels() (map[string]string, map[string]string, error) {
+	var rows []struct {
+		Name  string
+		Alias string
+	}
+	err := config.DB.Table("food_aliases").
+		Select("foods.name, food_aliases.alias").
+		Joins("JOIN foods ON foods.id = food_aliases.food_id").
+		Where("food_aliases.language = ?", helper.LangIndonesian).
+		Order("food_aliases.id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	labels := make(map[string]string)
+	names := make(map[string]string)
+	for _, row := range rows {
+		key := strings.ToLower(row.Name)
+		if _, found := labels[key]; !found {
+			labels[key] = row.Alias
+		}
+		names[strings.ToLower(row.Alias)] = row.Name
+	}
+	return labels, names, nil
+}
+
+// buildDataset turns saved scan sessions into images, categories and annotations.
+// Predictions are the raw ML detections, so misdetections can be found by comparing both.
+func buildDataset(sessions []models.ScanSession, labels, names map[string]string) *dataset {
+	data := &dataset{
+		Info: datasetInfo{
+			Description: "SweetLife confirmed food scans",
+			CreatedAt:   time.Now(),
+		},
+		Images:      []datasetImage{},
+		Categories:  []datasetCategory{},
+		Annotations: []datasetAnnotation{},
+		Predictions: []datasetAnnotation{},
+	}
+
+	categoryIDs := make(map[string]int)
+	category := func(name string) int {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if id, found := categoryIDs[key]; found {
+			return id
+		}
+		id := len(data.Categories) + 1
+		label := labels[key]
+		if label == "" {
+			label = name
+		}
+		categoryIDs[key] = id
+		data.Categories = append(data.Categories, datasetCategory{ID: id, Name: name, Label: label})
+		return id
+	}
+
+	for _, session := range sessions {
+		var confirmed, detected []dto.ScanFood
+		if err := json.Unmarshal([]byte(*session.ConfirmedItems), &confirmed); err != nil {
+			log.Printf("Skipping scan session %d: invalid confirmed items: %v", session.ID, err)
+			continue
+		}
+		if err := json.Unmarshal([]byte(session.RawObjects), &detected); err != nil {
+			log.Printf("Skipping scan session %d: invalid raw objects: %v", session.ID, err)
+			continue
+		}
+
+		data.Images = append(data.Images, datasetImage{
+			ID:        session.ID,
+			URL:       session.ImageURL,
+			FileName:  session.ImageObject,
+			ScannedAt: session.CreatedAt,
+		})
+
+		// ML labels are Indonesian, confirmed foods use catalog names
+		detectedFoods := make(map[string]bool)
+		for _, item := range detected {
+			name := item.Name
+			if food, found := names[strings.ToLower(item.Name)]; found {
+				name = food
+			}
+			detectedFoods[strings.ToLower(name)] = true
+			isDetected := true
+			data.Predictions = append(data.Predictions, datasetAnnotation{
+				ID:         len(data.Predictions) + 1,
+				ImageID:    session.ID,
+				CategoryID: category(name),
+				Unit:       item.Unit,
+				Detected:   &isDetected,
+			})
+		}
+
+		for _, item := range confirmed {
+			isDetected := detectedFoods[strings.ToLower(item.Name)]
+			data.Annotations = append(data.Annotations, datasetAnnotation{
+				ID:         len(data.Annotations) + 1,
+				ImageID:    session.ID,
+				CategoryID: category(item.Name),
+				Unit:       item.Unit,
+				Weight:     item.Weight,
+				Detected:   &isDetected,
+			})
+		}
+	}
+
+	return data
+}
+
+// writeCSV writes one row per confirmed annotation
+func writeCSV(w io.Writer, data *dataset) error {
+	images := make(map[uint]datasetImage, len(data.Images))
+	for _, image := range data.Images {
+		images[image.ID] = image
+	}
+	categories := make(map[int]datasetCategory, len(data.Categories))
+	for _, category := range data.Categories {
+		categories[category.ID] = category
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"image_id", "image_url", "food", "label", "unit", "weight", "detected"}); err != nil {
+		return err
+	}
+	for _, annotation := range data.Annotations {
+		weight := ""
+		if annotation.Weight != nil {
+			weight = strconv.FormatFloat(*annotation.Weight, 'f', 2, 64)
+		}
+		category := categories[annotation.CategoryID]
+		record := []string{
+			strconv.FormatUint(uint64(annotation.ImageID), 10),
+			images[annotation.ImageID].URL,
+			category.Name,
+			category.Label,
+			strconv.Itoa(annotation.Unit),
+			weight,
+			strconv.FormatBool(annotation.Detected != nil && *annotation.Detected),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
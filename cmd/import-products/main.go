@@ -0,0 +1,246 @@
+package main
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/config"
+	helper "github.com/rizkirmdhnnn/sweetlife-backend-go/helpers"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"gorm.io/gorm/clause"
+)
+
+// kilojoulesPerKilocalorie converts energy_100g, which Open Food Facts reports in kJ
+const kilojoulesPerKilocalorie = 4.184
+
+// batchSize is the number of products upserted at once
+const batchSize = 1000
+
+// columns of the Open Food Facts CSV export used for a product
+var columns = []string{
+	"code",
+	"product_name",
+	"brands",
+	"countries_tags",
+	"serving_quantity",
+	"energy-kcal_100g",
+	"energy_100g",
+	"fat_100g",
+	"carbohydrates_100g",
+	"sugars_100g",
+	"proteins_100g",
+}
+
+func main() {
+	file := flag.String("file", "", "Open Food Facts CSV export (en.openfoodfacts.org.products.csv), optionally gzipped")
+	country := flag.String("country", "", "only import products sold in this country, e.g. en:indonesia")
+	flag.Parse()
+
+	if *file == "" {
+		log.Fatal("Missing -file, download the CSV export from https://world.openfoodfacts.org/data")
+	}
+
+	// Load environment variables and database
+	config.LoadEnv()
+	config.LoadDatabase()
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatal("Failed to open product dump:", err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(*file, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			log.Fatal("Failed to open gzipped product dump:", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	// The export is tab separated and its fields are not quoted
+	reader := csv.NewReader(r)
+	reader.Comma = '\t'
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		log.Fatal("Failed to read product dump header:", err)
+	}
+	index, err := columnIndex(header)
+	if err != nil {
+		log.Fatal("Invalid product dump:", err)
+	}
+
+	// Upsert by barcode so foods already created for a product stay linked,
+	// their nutrition is refreshed from the product on the next lookup
+	successCount := 0
+	skipCount := 0
+	errorCount := 0
+	batch := make([]models.Product, 0, batchSize)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		err := config.DB.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "barcode"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "brand", "serving_weight", "calories", "sugar", "fat", "carbohydrates", "proteins", "updated_at"}),
+		}).Create(&batch).Error
+		if err != nil {
+			fmt.Printf("Failed to import %d products: %v\n", len(batch), err)
+			errorCount += len(batch)
+		} else {
+			successCount += len(batch)
+		}
+		batch = batch[:0]
+	}
+
+	// the same barcode can appear twice in the dump, a batch must not contain it twice
+	seen := make(map[string]bool)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			skipCount++
+			continue
+		}
+
+		get := func(column string) string {
+			i := index[column]
+			if i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		if *country != "" && !hasTag(get("countries_tags"), *country) {
+			continue
+		}
+
+		product, ok := parseProduct(get)
+		if !ok || seen[product.Barcode] {
+			skipCount++
+			continue
+		}
+		seen[product.Barcode] = true
+
+		batch = append(batch, product)
+		if len(batch) == batchSize {
+			flush()
+			seen = make(map[string]bool)
+		}
+	}
+	flush()
+
+	fmt.Printf("\nImport completed!\n")
+	fmt.Printf("Successfully imported: %d products\n", successCount)
+	fmt.Printf("Skipped: %d products without a valid barcode, name or nutrition\n", skipCount)
+	fmt.Printf("Failed to import: %d products\n", errorCount)
+}
+
+// columnIndex maps the used columns to their position in the header
+func columnIndex(header []string) (map[string]int, error) {
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		positions[strings.TrimSpace(name)] = i
+	}
+
+	index := make(map[string]int, len(columns))
+	for _, name := range columns {
+		i, found := positions[name]
+		if !found {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+		index[name] = i
+	}
+	return index, nil
+}
+
+// parseProduct reads a product, products without a valid barcode, a name or energy are skipped
+func parseProduct(get func(string) string) (models.Product, bool) {
+	barcode, ok := helper.NormalizeBarcode(get("code"))
+	if !ok {
+		return models.Product{}, false
+	}
+
+	name := get("product_name")
+	if name == "" {
+		return models.Product{}, false
+	}
+
+	calories, ok := parseNumber(get("energy-kcal_100g"))
+	if !ok {
+		kilojoules, found := parseNumber(get("energy_100g"))
+		if !found {
+			return models.Product{}, false
+		}
+		calories = kilojoules / kilojoulesPerKilocalorie
+	}
+
+	// only the first brand is kept, brands are a comma separated list
+	brand := strings.TrimSpace(strings.Split(get("brands"), ",")[0])
+
+	serving, _ := parseNumber(get("serving_quantity"))
+	fat, _ := parseNumber(get("fat_100g"))
+	carbohydrates, _ := parseNumber(get("carbohydrates_100g"))
+	sugar, _ := parseNumber(get("sugars_100g"))
+	proteins, _ := parseNumber(get("proteins_100g"))
+
+	return models.Product{
+		Barcode:       barcode,
+		Name:          truncate(name, 255),
+		Brand:         truncate(brand, 255),
+		ServingWeight: serving,
+		Calories:      calories,
+		Sugar:         sugar,
+		Fat:           fat,
+		Carbohydrates: carbohydrates,
+		Proteins:      proteins,
+	}, true
+}
+
+// parseNumber parses a non negative nutrition value
+func parseNumber(value string) (float64, bool) {
+	if value == "" {
+		return 0, false
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || number < 0 {
+		return 0, false
+	}
+	return number, true
+}
+
+// hasTag reports whether a comma separated tag list contains the tag
+func hasTag(tags, tag string) bool {
+	for _, t := range strings.Split(tags, ",") {
+		if strings.EqualFold(strings.TrimSpace(t), tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// truncate shortens a value to fit its column
+func truncate(value string, max int) string {
+	runes := []rune(value)
+	if len(runes) <= max {
+		return value
+	}
+	return string(runes[:max])
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/config"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"gorm.io/gorm/clause"
+)
+
+func main() {
+	// Load environment variables and database
+	config.LoadEnv()
+	config.LoadDatabase()
+
+	// Open the exercise catalog
+	file, err := os.Open("data/exercises.json")
+	if err != nil {
+		log.Fatal("Failed to open exercise catalog:", err)
+	}
+	defer file.Close()
+
+	var exercises []models.Exercise
+	if err := json.NewDecoder(file).Decode(&exercises); err != nil {
+		log.Fatal("Failed to decode exercise catalog:", err)
+	}
+
+	fmt.Printf("Found %d exercises to import\n", len(exercises))
+
+	// Upsert by name so existing IDs referenced by activity logs stay the same
+	successCount := 0
+	errorCount := 0
+
+	for i, exercise := range exercises {
+		exercise.Name = strings.TrimSpace(exercise.Name)
+		if exercise.Name == "" || exercise.Description == "" || exercise.MET <= 0 {
+			fmt.Printf("Skipping exercise %d: missing required fields\n", i+1)
+			errorCount++
+			continue
+		}
+
+		err := config.DB.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"description", "description_indo", "met", "image", "updated_at"}),
+		}).Create(&exercise).Error
+		if err != nil {
+			fmt.Printf("Failed to import exercise %s: %v\n", exercise.Name, err)
+			errorCount++
+			continue
+		}
+
+		successCount++
+	}
+
+	fmt.Printf("\nMigration completed!\n")
+	fmt.Printf("Successfully imported: %d exercises\n", successCount)
+	fmt.Printf("Failed to import: %d exercises\n", errorCount)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/config"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+)
+
+type foodAlias struct {
+	Food     string `json:"food"`
+	Language string `json:"language"`
+	Alias    string `json:"alias"`
+}
+
+func main() {
+	// Load environment variables and database
+	config.LoadEnv()
+	config.LoadDatabase()
+
+	// Open the alias list
+	file, err := os.Open("data/food_aliases.json")
+	if err != nil {
+		log.Fatal("Failed to open food aliases:", err)
+	}
+	defer file.Close()
+
+	var aliases []foodAlias
+	if err := json.NewDecoder(file).Decode(&aliases); err != nil {
+		log.Fatal("Failed to decode food aliases:", err)
+	}
+
+	fmt.Printf("Found %d aliases to import\n", len(aliases))
+
+	// Aliases of foods that are not in the database yet are skipped, run again after importing them
+	successCount := 0
+	errorCount := 0
+
+	for i, alias := range aliases {
+		alias.Food = strings.TrimSpace(alias.Food)
+		alias.Alias = strings.TrimSpace(alias.Alias)
+		if alias.Food == "" || alias.Alias == "" || alias.Language == "" {
+			fmt.Printf("Skipping alias %d: missing required fields\n", i+1)
+			errorCount++
+			continue
+		}
+
+		var food models.Food
+		if err := config.DB.Where("owner_id IS NULL AND LOWER(name) = LOWER(?)", alias.Food).Order("id").First(&food).Error; err != nil {
+			fmt.Printf("Skipping alias %s: food %s not found\n", alias.Alias, alias.Food)
+			errorCount++
+			continue
+		}
+
+		// the unique index is on the lower case alias, so the conflict is checked by hand
+		var existing models.FoodAlias
+		err := config.DB.Where("LOWER(alias) = LOWER(?)", alias.Alias).First(&existing).Error
+		if err == nil {
+			existing.FoodID = food.ID
+			existing.Language = alias.Language
+			existing.Alias = alias.Alias
+			err = config.DB.Save(&existing).Error
+		} else {
+			err = config.DB.Create(&models.FoodAlias{
+				FoodID:   food.ID,
+				Language: alias.Language,
+				Alias:    alias.Alias,
+			}).Error
+		}
+		if err != nil {
+			fmt.Printf("Failed to import alias %s: %v\n", alias.Alias, err)
+			errorCount++
+			continue
+		}
+
+		successCount++
+	}
+
+	fmt.Printf("\nMigration completed!\n")
+	fmt.Printf("Successfully imported: %d aliases\n", successCount)
+	fmt.Printf("Failed to import: %d aliases\n", errorCount)
+}
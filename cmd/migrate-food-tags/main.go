@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/config"
+	helper "github.com/rizkirmdhnnn/sweetlife-backend-go/helpers"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"gorm.io/gorm"
+)
+
+type foodTags struct {
+	Food string   `json:"food"`
+	Tags []string `json:"tags"`
+}
+
+func main() {
+	// Load environment variables and database
+	config.LoadEnv()
+	config.LoadDatabase()
+
+	// Open the tag list
+	file, err := os.Open("data/food_tags.json")
+	if err != nil {
+		log.Fatal("Failed to open food tags:", err)
+	}
+	defer file.Close()
+
+	var foods []foodTags
+	if err := json.NewDecoder(file).Decode(&foods); err != nil {
+		log.Fatal("Failed to decode food tags:", err)
+	}
+
+	fmt.Printf("Found %d foods to tag\n", len(foods))
+
+	// Foods that are not in the catalog, like the dishes of the ML service, are tagged by name.
+	// The tags of a food are replaced, so removing a tag from the list removes it from the food.
+	successCount := 0
+	errorCount := 0
+
+	for i, item := range foods {
+		item.Food = strings.TrimSpace(item.Food)
+		if item.Food == "" || len(item.Tags) == 0 {
+			fmt.Printf("Skipping food %d: missing required fields\n", i+1)
+			errorCount++
+			continue
+		}
+
+		if err := config.DB.Transaction(func(tx *gorm.DB) error {
+			var food models.Food
+			err := tx.Where("owner_id IS NULL AND LOWER(name) = LOWER(?)", item.Food).Order("id").First(&food).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return tagFoodName(tx, item.Food, item.Tags)
+			}
+			if err != nil {
+				return err
+			}
+			return tagFood(tx, &food, item.Tags)
+		}); err != nil {
+			fmt.Printf("Failed to tag food %s: %v\n", item.Food, err)
+			errorCount++
+			continue
+		}
+
+		successCount++
+	}
+
+	fmt.Printf("\nMigration completed!\n")
+	fmt.Printf("Successfully tagged: %d foods\n", successCount)
+	fmt.Printf("Failed to tag: %d foods\n", errorCount)
+}
+
+// tagFood replaces the tags of a food
+func tagFood(tx *gorm.DB, food *models.Food, names []string) error {
+	tags, err := findTags(tx, names)
+	if err != nil {
+		return err
+	}
+	return tx.Model(food).Association("Tags").Replace(tags)
+}
+
+// tagFoodName replaces the tags of a food that is not in the catalog
+func tagFoodName(tx *gorm.DB, name string, names []string) error {
+	tags, err := findTags(tx, names)
+	if err != nil {
+		return err
+	}
+
+	name = strings.ToLower(name)
+	if err := tx.Where("name = ?", name).Delete(&models.FoodNameTag{}).Error; err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if err := tx.Create(&models.FoodNameTag{Name: name, TagID: tag.ID}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findTags finds or creates the tags, unknown tags are rejected so typos do not silently pass the dietary filter
+func findTags(tx *gorm.DB, names []string) ([]models.FoodTag, error) {
+	tags := make([]models.FoodTag, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !helper.IsKnownFoodTag(name) {
+			return nil, fmt.Errorf("unknown tag %q", name)
+		}
+
+		tag := models.FoodTag{Name: name}
+		if err := tx.Where("name = ?", name).FirstOrCreate(&tag).Error; err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
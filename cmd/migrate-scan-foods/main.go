@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/config"
+	helper "github.com/rizkirmdhnnn/sweetlife-backend-go/helpers"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"gorm.io/gorm"
+)
+
+func main() {
+	// Load environment variables and database
+	config.LoadEnv()
+	config.LoadDatabase()
+
+	// Open the scan nutrition catalog
+	file, err := os.Open("data/nutritions.json")
+	if err != nil {
+		log.Fatal("Failed to open nutrition catalog:", err)
+	}
+	defer file.Close()
+
+	var foods []models.ScanFood
+	if err := json.NewDecoder(file).Decode(&foods); err != nil {
+		log.Fatal("Failed to decode nutrition catalog:", err)
+	}
+
+	fmt.Printf("Found %d foods to import\n", len(foods))
+
+	// Upsert by name so existing IDs referenced by food histories stay the same
+	successCount := 0
+	errorCount := 0
+
+	for i, food := range foods {
+		food.Name = strings.TrimSpace(food.Name)
+		if food.Name == "" || food.Weight <= 0 {
+			fmt.Printf("Skipping food %d: missing required fields\n", i+1)
+			errorCount++
+			continue
+		}
+
+		if err := config.DB.Transaction(func(tx *gorm.DB) error {
+			return importFood(tx, food)
+		}); err != nil {
+			fmt.Printf("Failed to import food %s: %v\n", food.Name, err)
+			errorCount++
+			continue
+		}
+
+		successCount++
+	}
+
+	fmt.Printf("\nMigration completed!\n")
+	fmt.Printf("Successfully imported: %d foods\n", successCount)
+	fmt.Printf("Failed to import: %d foods\n", errorCount)
+}
+
+// importFood creates or updates a food, its nutrition per portion weight and its Indonesian alias
+func importFood(tx *gorm.DB, item models.ScanFood) error {
+	var food models.Food
+	err := tx.Where("owner_id IS NULL AND LOWER(name) = LOWER(?)", item.Name).Order("id").First(&food).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		food = models.Food{Name: item.Name}
+		err = tx.Create(&food).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	var nutrition models.FoodNutrition
+	err = tx.Where("food_id = ?", food.ID).First(&nutrition).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	nutrition.FoodID = food.ID
+	nutrition.Weight = item.Weight
+	nutrition.Calories = item.Calories
+	nutrition.Proteins = item.Protein
+	nutrition.Sugar = item.Sugar
+	nutrition.Carbohydrates = item.Carbohydrates
+	nutrition.Fat = item.Fat
+	if err := tx.Save(&nutrition).Error; err != nil {
+		return err
+	}
+
+	// the ML service labels foods with their Indonesian name
+	if item.NameIndo == nil || strings.TrimSpace(*item.NameIndo) == "" {
+		return nil
+	}
+	alias := strings.TrimSpace(*item.NameIndo)
+
+	var existing models.FoodAlias
+	err = tx.Where("LOWER(alias) = LOWER(?)", alias).First(&existing).Error
+	if err == nil {
+		existing.FoodID = food.ID
+		existing.Language = helper.LangIndonesian
+		return tx.Save(&existing).Error
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return tx.Create(&models.FoodAlias{
+		FoodID:   food.ID,
+		Language: helper.LangIndonesian,
+		Alias:    alias,
+	}).Error
+}
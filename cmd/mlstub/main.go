@@ -0,0 +1,21 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/mlstub"
+)
+
+func main() {
+	addr := flag.String("addr", ":5000", "address the stub ML service listens on")
+	flag.Parse()
+
+	// Log and start the stub server
+	log.Println("ML stub server started on", *addr)
+	log.Println("Set ML_BASE_URL to this address to use it from the backend")
+	if err := http.ListenAndServe(*addr, mlstub.NewHandler()); err != nil {
+		log.Fatal("Failed to start ML stub server:", err)
+	}
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"log"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/cache"
+)
+
+var Cache cache.Cache
+
+func LoadCache() {
+	// Use in-memory cache when redis is not configured
+	if ENV.REDIS_URL == "" {
+		Cache = cache.NewMemoryCache(1000)
+		log.Println("Cache loaded (in-memory)")
+		return
+	}
+
+	// Connect to redis, fall back to in-memory cache if it is unreachable
+	redisCache, err := cache.NewRedisCache(ENV.REDIS_URL)
+	if err != nil {
+		log.Printf("Failed to connect to redis, using in-memory cache: %v", err)
+		Cache = cache.NewMemoryCache(1000)
+		return
+	}
+
+	Cache = redisCache
+	log.Println("Cache loaded (redis)")
+}
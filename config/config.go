@@ -35,6 +35,15 @@ type Env struct {
 	GOOGLE_CREDENTIALS_BASE64 string
 
 	USDA_API_KEY string
+
+	ML_BASE_URL      string
+	ML_MODEL_VERSION string
+
+	REDIS_URL                string
+	RECOMMENDATION_CACHE_TTL string
+
+	SCAN_WORKERS    string
+	SCAN_QUEUE_SIZE string
 }
 
 func LoadEnv() {
@@ -70,6 +79,15 @@ func LoadEnv() {
 		GOOGLE_CREDENTIALS_BASE64: getEnv("GOOGLE_CREDENTIALS_BASE64", ""),
 
 		USDA_API_KEY: getEnv("USDA_API_KEY", ""),
+
+		ML_BASE_URL:      getEnv("ML_BASE_URL", "https://ml.sweetlife.my.id"),
+		ML_MODEL_VERSION: getEnv("ML_MODEL_VERSION", "v1"),
+
+		REDIS_URL:                getEnv("REDIS_URL", ""),
+		RECOMMENDATION_CACHE_TTL: getEnv("RECOMMENDATION_CACHE_TTL", "6h"),
+
+		SCAN_WORKERS:    getEnv("SCAN_WORKERS", "4"),
+		SCAN_QUEUE_SIZE: getEnv("SCAN_QUEUE_SIZE", "100"),
 	}
 
 	if ENV.APP_ENV == "development" {
@@ -39,13 +39,44 @@ func LoadDatabase() {
 		models.RiskAssessment{},
 		models.Food{},
 		models.FoodNutrition{},
+		models.ScanSession{},
 		models.UserFoodHistory{},
 		models.MiniCourse{},
 		models.MiniGrocery{},
+		models.Exercise{},
+		models.ActivityLog{},
+		models.ExercisePlan{},
+		models.ExercisePlanSession{},
+		models.MealPlan{},
+		models.MealPlanItem{},
+		models.FoodTag{},
+		models.FoodNameTag{},
+		models.UserDietaryPreference{},
+		models.RecommendationFeedback{},
+		models.FoodAlias{},
+		models.UnmatchedScanLabel{},
+		models.Product{},
+		models.Recipe{},
+		models.RecipeIngredient{},
+		models.FavoriteFood{},
 	); err != nil {
 		log.Fatal("Failed to migrate table")
 	}
 
+	// Create trigram and full-text indexes for food search, search does not work without them
+	for _, statement := range []string{
+		"CREATE EXTENSION IF NOT EXISTS pg_trgm;",
+		"CREATE INDEX IF NOT EXISTS idx_foods_name_trgm ON foods USING gin (LOWER(name) gin_trgm_ops);",
+		"CREATE INDEX IF NOT EXISTS idx_foods_name_fts ON foods USING gin (to_tsvector('simple', name));",
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_food_aliases_alias ON food_aliases (LOWER(alias));",
+		"CREATE INDEX IF NOT EXISTS idx_food_aliases_alias_trgm ON food_aliases USING gin (LOWER(alias) gin_trgm_ops);",
+		"CREATE INDEX IF NOT EXISTS idx_food_aliases_alias_fts ON food_aliases USING gin (to_tsvector('simple', alias));",
+	} {
+		if err := db.Exec(statement).Error; err != nil {
+			log.Fatalf("Failed to create food search index: %v", err)
+		}
+	}
+
 	DB = db
 	log.Println("Database connected")
 }
@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+)
+
+type CreateActivityRequest struct {
+	ExerciseID  uint                     `json:"exercise_id" binding:"required"`
+	Duration    int                      `json:"duration" binding:"required,min=1,max=1440"`
+	Intensity   models.ActivityIntensity `json:"intensity" binding:"omitempty,oneof=light moderate vigorous"`
+	PerformedAt *time.Time               `json:"performed_at"`
+}
+
+type ActivityResponse struct {
+	ID             uint                     `json:"id"`
+	ExerciseID     uint                     `json:"exercise_id"`
+	ExerciseName   string                   `json:"exercise_name"`
+	Image          string                   `json:"image"`
+	Duration       int                      `json:"duration"`
+	Intensity      models.ActivityIntensity `json:"intensity"`
+	CaloriesBurned float64                  `json:"calories_burned"`
+	PerformedAt    time.Time                `json:"performed_at"`
+}
+
+type DailyActivityResponse struct {
+	Date                string             `json:"date"`
+	TotalDuration       int                `json:"total_duration"`
+	TotalCaloriesBurned float64            `json:"total_calories_burned"`
+	Activities          []ActivityResponse `json:"activities"`
+}
+
+type ExerciseCatalogResponse struct {
+	ID    uint    `json:"id"`
+	Name  string  `json:"name"`
+	Desc  string  `json:"desc"`
+	MET   float64 `json:"met"`
+	Image string  `json:"image"`
+}
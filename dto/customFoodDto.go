@@ -0,0 +1,32 @@
+package dto
+
+import "time"
+
+// CustomFoodRequest is a food entered by the user, e.g. from a nutrition label.
+// Nutrition is per 100 gram, or per serving of ServingWeight gram when Per is "serving".
+type CustomFoodRequest struct {
+	Name          string  `json:"name" binding:"required"`
+	Per           string  `json:"per"` // 100g (default) or serving
+	ServingWeight float64 `json:"serving_weight"`
+	Calories      float64 `json:"calories"`
+	Protein       float64 `json:"protein"`
+	Sugar         float64 `json:"sugar"`
+	Carbohydrates float64 `json:"carbohydrates"`
+	Fat           float64 `json:"fat"`
+	Visibility    string  `json:"visibility"` // private (default) or public
+}
+
+// CustomFoodResponse is a custom food with its nutrition per Weight gram
+type CustomFoodResponse struct {
+	ID            uint      `json:"id"`
+	Name          string    `json:"name"`
+	Visibility    string    `json:"visibility"`
+	Weight        float64   `json:"weight"`
+	Calories      float64   `json:"calories"`
+	Protein       float64   `json:"protein"`
+	Sugar         float64   `json:"sugar"`
+	Carbohydrates float64   `json:"carbohydrates"`
+	Fat           float64   `json:"fat"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
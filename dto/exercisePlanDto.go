@@ -0,0 +1,24 @@
+package dto
+
+type ExercisePlanResponse struct {
+	WeekStart         string                 `json:"week_start"`
+	WeekEnd           string                 `json:"week_end"`
+	CaloriesBurned    float64                `json:"calories_burned"`
+	SessionDuration   int                    `json:"session_duration"`
+	TotalSessions     int                    `json:"total_sessions"`
+	CompletedSessions int                    `json:"completed_sessions"`
+	Sessions          []ExercisePlanSessions `json:"sessions"`
+}
+
+type ExercisePlanSessions struct {
+	ID               uint    `json:"id"`
+	Date             string  `json:"date"`
+	Day              string  `json:"day"`
+	ExerciseID       *uint   `json:"exercise_id"`
+	ExerciseName     string  `json:"exercise_name"`
+	Image            string  `json:"image"`
+	Duration         int     `json:"duration"`
+	TargetCalories   float64 `json:"target_calories"`
+	CompletedMinutes int     `json:"completed_minutes"`
+	Completed        bool    `json:"completed"`
+}
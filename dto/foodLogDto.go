@@ -0,0 +1,56 @@
+package dto
+
+import "time"
+
+// FrequentFood is a pinned or often logged food with the nutrition of its last portion
+type FrequentFood struct {
+	FoodID        uint       `json:"food_id"`
+	Name          string     `json:"name"`
+	Pinned        bool       `json:"pinned"`
+	Count         int        `json:"count"`
+	LastLoggedAt  *time.Time `json:"last_logged_at"`
+	Unit          int        `json:"unit"`
+	Weight        float64    `json:"weight"`
+	Calories      float64    `json:"calories"`
+	Protein       float64    `json:"protein"`
+	Sugar         float64    `json:"sugar"`
+	Carbohydrates float64    `json:"carbohydrates"`
+	Fat           float64    `json:"fat"`
+}
+
+type FavoriteFoodRequest struct {
+	FoodID uint `json:"food_id" binding:"required"`
+}
+
+// QuickLogRequest logs a food again, without unit and weight the last portion is used
+type QuickLogRequest struct {
+	FoodID uint     `json:"food_id" binding:"required"`
+	Unit   int      `json:"unit"`
+	Weight *float64 `json:"weight"`
+	Meal   string   `json:"meal"` // optional
+}
+
+// FoodLogEntry is a logged food with the nutrition of its portion
+type FoodLogEntry struct {
+	ID            uint      `json:"id"`
+	FoodID        uint      `json:"food_id"`
+	Name          string    `json:"name"`
+	Meal          string    `json:"meal,omitempty"`
+	Unit          int       `json:"unit"`
+	Weight        float64   `json:"weight"`
+	Calories      float64   `json:"calories"`
+	Protein       float64   `json:"protein"`
+	Sugar         float64   `json:"sugar"`
+	Carbohydrates float64   `json:"carbohydrates"`
+	Fat           float64   `json:"fat"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// CopyFoodLogRequest copies the foods logged on a date, or at one meal of it, to another date.
+// Dates are YYYY-MM-DD, To defaults to today. Entries logged before meals were recorded
+// have no meal, they are only copied when Meal is empty.
+type CopyFoodLogRequest struct {
+	From string `json:"from" binding:"required"`
+	To   string `json:"to"`
+	Meal string `json:"meal"` // optional, all meals when empty
+}
@@ -12,6 +12,9 @@ type HealthProfileDto struct {
 	HasHeartDisease bool                  `json:"has_heart_disease"`
 	ActivityLevel   models.ActivityLevel  `json:"activity_level"`
 
+	// DietaryPreferences replaces the stored preferences, nil keeps them unchanged
+	DietaryPreferences []models.DietaryPreference `json:"dietary_preferences"`
+
 	// Diabetes details
 	DiabeticType  models.DiabeticType `json:"diabetic_type"`
 	InsulinLevel  float64             `json:"insulin_level"`
@@ -31,12 +34,13 @@ type DiabetesDetails struct {
 }
 
 type HealthProfileResponse struct {
-	Height             float64               `json:"height"`
-	Weight             float64               `json:"weight"`
-	IsDiabetic         bool                  `json:"is_diabetic"`
-	DiabetesDetails    *DiabetesDetails      `json:"diabetes_details,omitempty"`
-	SmokingHistory     models.SmokingHistory `json:"smoking_history"`
-	HasHeartDisease    bool                  `json:"has_heart_disease"`
-	ActivityLevel      models.ActivityLevel  `json:"activity_level"`
-	DiabetesPrediction *DiabetesPrediction   `json:"diabetes_prediction,omitempty"`
+	Height             float64                    `json:"height"`
+	Weight             float64                    `json:"weight"`
+	IsDiabetic         bool                       `json:"is_diabetic"`
+	DiabetesDetails    *DiabetesDetails           `json:"diabetes_details,omitempty"`
+	SmokingHistory     models.SmokingHistory      `json:"smoking_history"`
+	HasHeartDisease    bool                       `json:"has_heart_disease"`
+	ActivityLevel      models.ActivityLevel       `json:"activity_level"`
+	DiabetesPrediction *DiabetesPrediction        `json:"diabetes_prediction,omitempty"`
+	DietaryPreferences []models.DietaryPreference `json:"dietary_preferences"`
 }
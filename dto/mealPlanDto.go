@@ -0,0 +1,43 @@
+package dto
+
+type MealPlanResponse struct {
+	ID      uint              `json:"id"`
+	Date    string            `json:"date"`
+	Targets MealPlanNutrition `json:"targets"`
+	Totals  MealPlanNutrition `json:"totals"`
+	// Difference is totals minus targets, positive when the plan goes over a target
+	Difference MealPlanNutrition `json:"difference"`
+	// WithinTargets is false when the portions could not be fitted to the targets
+	WithinTargets bool           `json:"within_targets"`
+	Meals         []MealPlanMeal `json:"meals"`
+}
+
+type MealPlanNutrition struct {
+	Calories      float64 `json:"calories"`
+	Sugar         float64 `json:"sugar"`
+	Carbohydrates float64 `json:"carbohydrates"`
+}
+
+type MealPlanMeal struct {
+	Meal           string         `json:"meal"`
+	TargetCalories float64        `json:"target_calories"`
+	Items          []MealPlanItem `json:"items"`
+}
+
+type MealPlanItem struct {
+	ID            uint    `json:"id"`
+	FoodID        *uint   `json:"food_id"`
+	Name          string  `json:"name"`
+	Image         string  `json:"image"`
+	Portion       float64 `json:"portion"`
+	Weight        float64 `json:"weight"`
+	Calories      float64 `json:"calories"`
+	Sugar         float64 `json:"sugar"`
+	Carbohydrates float64 `json:"carbohydrates"`
+	Fat           float64 `json:"fat"`
+	Proteins      float64 `json:"proteins"`
+}
+
+type SwapMealPlanItemRequest struct {
+	FoodID *uint `json:"food_id"`
+}
@@ -0,0 +1,52 @@
+package dto
+
+import "time"
+
+type RecipeIngredientRequest struct {
+	FoodID uint    `json:"food_id" binding:"required"`
+	Weight float64 `json:"weight" binding:"required"` // grams
+}
+
+// RecipeRequest creates or updates a recipe, Servings defaults to 1
+type RecipeRequest struct {
+	Name        string                    `json:"name" binding:"required"`
+	Servings    float64                   `json:"servings"`
+	Visibility  string                    `json:"visibility"` // private (default) or public
+	Ingredients []RecipeIngredientRequest `json:"ingredients" binding:"required,min=1,dive"`
+}
+
+// RecipeNutrition is the nutrition of Weight grams of a recipe or an ingredient
+type RecipeNutrition struct {
+	Weight        float64 `json:"weight"`
+	Calories      float64 `json:"calories"`
+	Protein       float64 `json:"protein"`
+	Sugar         float64 `json:"sugar"`
+	Carbohydrates float64 `json:"carbohydrates"`
+	Fat           float64 `json:"fat"`
+}
+
+type RecipeIngredientResponse struct {
+	FoodID uint   `json:"food_id"`
+	Name   string `json:"name"`
+	RecipeNutrition
+}
+
+type RecipeResponse struct {
+	ID          uint                       `json:"id"`
+	FoodID      uint                       `json:"food_id"`
+	Name        string                     `json:"name"`
+	Visibility  string                     `json:"visibility"`
+	Owned       bool                       `json:"owned"`
+	Servings    float64                    `json:"servings"`
+	Total       RecipeNutrition            `json:"total"`
+	PerServing  RecipeNutrition            `json:"per_serving"`
+	Ingredients []RecipeIngredientResponse `json:"ingredients"`
+	CreatedAt   time.Time                  `json:"created_at"`
+	UpdatedAt   time.Time                  `json:"updated_at"`
+}
+
+// RecipeServing logs servings of a recipe, Servings defaults to 1
+type RecipeServing struct {
+	RecipeID uint    `json:"recipe_id" binding:"required"`
+	Servings float64 `json:"servings"`
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+
 type DiabetesPredictionRequest struct {
 	Age            int     `json:"age"`
 	HeartDisease   bool    `json:"heart_disease"`
@@ -18,6 +20,7 @@ type FoodRecomendationClientResp struct {
 	FoodRecomendation [][]FoodClientResp `json:"food_recommendation"`
 }
 
+// FoodClientResp is a recommended food, nutrition is given for one serving
 type FoodClientResp struct {
 	Calories     float64 `json:"calories"`
 	Carbohydrate float64 `json:"carbohydrate"`
@@ -39,9 +42,11 @@ type ExerciseRecommendation struct {
 }
 
 type ExerciseList struct {
-	Name  string `json:"name"`
-	Desc  string `json:"desc"`
-	Image string `json:"image"`
+	ID    uint    `json:"id,omitempty"`
+	Name  string  `json:"name"`
+	Desc  string  `json:"desc"`
+	MET   float64 `json:"met,omitempty"`
+	Image string  `json:"image"`
 }
 
 type ExerciseRecommendationClientResp struct {
@@ -70,3 +75,15 @@ type RecomendationDetails struct {
 	Fat          string `json:"fat"`
 	Proteins     string `json:"proteins"`
 }
+
+type RecommendationFeedbackRequest struct {
+	Type   models.FeedbackTarget `json:"type" binding:"required"`
+	Name   string                `json:"name" binding:"required"`
+	Rating string                `json:"rating" binding:"required"` // like, dislike or none to remove the rating
+}
+
+type AteFoodRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	Unit   int      `json:"unit"`
+	Weight *float64 `json:"weight"`
+}
@@ -1,25 +1,63 @@
 package dto
 
+// USDANutrientClient is a nutrient of a food in the USDA API search results, values are per 100 gram
+type USDANutrientClient struct {
+	NutrientName   string  `json:"nutrientName"`
+	NutrientNumber string  `json:"nutrientNumber"`
+	Value          float64 `json:"value"`
+	UnitName       string  `json:"unitName"`
+}
+
+type USDAFoodClient struct {
+	FdcID           int                  `json:"fdcId"`
+	Description     string               `json:"description"`
+	DataType        string               `json:"dataType"`
+	BrandOwner      string               `json:"brandOwner"`
+	FoodCategory    string               `json:"foodCategory"`
+	ServingSize     float64              `json:"servingSize"`
+	ServingSizeUnit string               `json:"servingSizeUnit"`
+	FoodNutrients   []USDANutrientClient `json:"foodNutrients"`
+}
+
 type FoodNutritionResponseClient struct {
-	Foods []struct {
-		Description   string `json:"description"`
-		FoodCategory  string `json:"foodCategory"`
-		FoodNutrients []struct {
-			NutrientName string  `json:"nutrientName"`
-			Value        float64 `json:"value"`
-			UnitName     string  `json:"unitName"`
-		} `json:"foodNutrients"`
-	} `json:"foods"`
+	Foods []USDAFoodClient `json:"foods"`
+}
+
+// USDAFoodDetailClient is a single food of the USDA API, nutrients are nested unlike in the search results
+type USDAFoodDetailClient struct {
+	FdcID           int     `json:"fdcId"`
+	Description     string  `json:"description"`
+	DataType        string  `json:"dataType"`
+	BrandOwner      string  `json:"brandOwner"`
+	ServingSize     float64 `json:"servingSize"`
+	ServingSizeUnit string  `json:"servingSizeUnit"`
+	FoodCategory    *struct {
+		Description string `json:"description"`
+	} `json:"foodCategory"`
+	FoodNutrients []struct {
+		Nutrient struct {
+			Name     string `json:"name"`
+			Number   string `json:"number"`
+			UnitName string `json:"unitName"`
+		} `json:"nutrient"`
+		Amount float64 `json:"amount"`
+	} `json:"foodNutrients"`
 }
 
+// FoodNutritionResponse is a USDA food the user can pick, nutrition is given for Weight gram
 type FoodNutritionResponse struct {
-	Name     string  `json:"name"`
-	Calories float64 `json:"calories"`
-	Protein  float64 `json:"protein"`
-	Fat      float64 `json:"fat"`
-	Carbs    float64 `json:"carbs"`
-	Sugar    float64 `json:"sugar"`
-	Weight   float64 `json:"weight"`
+	FdcID         int     `json:"fdc_id"`
+	Name          string  `json:"name"`
+	DataType      string  `json:"data_type"`
+	Brand         string  `json:"brand,omitempty"`
+	Category      string  `json:"category,omitempty"`
+	ServingWeight float64 `json:"serving_weight,omitempty"`
+	Calories      float64 `json:"calories"`
+	Protein       float64 `json:"protein"`
+	Fat           float64 `json:"fat"`
+	Carbs         float64 `json:"carbs"`
+	Sugar         float64 `json:"sugar"`
+	Weight        float64 `json:"weight"`
 }
 
 type ScanFoodClientResp struct {
@@ -29,21 +67,49 @@ type ScanFoodClientResp struct {
 type ScanFood struct {
 	Name string `json:"name"`
 	Unit int    `json:"unit"`
+	// Weight is the total weight in grams adjusted by the user, only used when saving
+	Weight *float64 `json:"weight,omitempty"`
 }
 
 type ScanFoodResponse struct {
-	IsDetected bool       `json:"is_detected"`
-	FoodList   []FoodList `json:"food_list"`
+	ScanSessionID uint            `json:"scan_session_id"`
+	IsDetected    bool            `json:"is_detected"`
+	FoodList      []FoodList      `json:"food_list"`
+	Unmatched     []UnmatchedFood `json:"unmatched"`
+}
+
+// ScanJobResponse is the state of a scan, Result is only set once the scan is done
+type ScanJobResponse struct {
+	ScanSessionID uint              `json:"scan_session_id"`
+	Status        string            `json:"status"`
+	Error         string            `json:"error,omitempty"`
+	Result        *ScanFoodResponse `json:"result,omitempty"`
+}
+
+// UnmatchedFood is a detected food missing from the catalog, with foods the user can pick instead
+type UnmatchedFood struct {
+	Label      string             `json:"label"`
+	Unit       int                `json:"unit"`
+	Candidates []FoodSearchResult `json:"candidates"`
 }
 
 type FoodList struct {
-	Name         string  `json:"name"`
-	Unit         int     `json:"unit"`
-	Calories     float64 `json:"calories"`
-	Protein      float64 `json:"protein"`
-	Sugar        float64 `json:"sugar"`
-	Carbohydrate float64 `json:"carbohydrate"`
-	Fat          float64 `json:"fat"`
+	Name         string          `json:"name"`
+	Unit         int             `json:"unit"`
+	Portion      string          `json:"portion"`
+	Weight       float64         `json:"weight"`
+	Portions     []PortionOption `json:"portions"`
+	Calories     float64         `json:"calories"`
+	Protein      float64         `json:"protein"`
+	Sugar        float64         `json:"sugar"`
+	Carbohydrate float64         `json:"carbohydrate"`
+	Fat          float64         `json:"fat"`
+}
+
+// PortionOption is the weight in grams of one unit of a food in a portion size
+type PortionOption struct {
+	Size   string  `json:"size"`
+	Weight float64 `json:"weight"`
 }
 
 type FindFoodClientResp struct {
@@ -64,12 +130,38 @@ type NutritionInfoClientResp struct {
 type FindFoodRequest struct {
 	Name   string  `json:"name"`
 	Weight float64 `json:"weight"`
+	// FdcID is the USDA food picked by the user for a food missing from the database,
+	// the best ranked USDA food is used when not set
+	FdcID int `json:"fdc_id"`
+}
+
+// BarcodeRequest looks up a packaged food, Weight defaults to one serving or 100 gram
+type BarcodeRequest struct {
+	Barcode string  `json:"barcode" binding:"required"`
+	Weight  float64 `json:"weight"`
 }
 
 type SaveFoodRequest struct {
-	Scan        []ScanFood `json:"scan"`
-	Additionall []struct {
+	// ScanSessionID is the scan the scanned foods come from, if any
+	ScanSessionID *uint      `json:"scan_session_id"`
+	Scan          []ScanFood `json:"scan"`
+	Additionall   []struct {
 		Name   string  `json:"name"`
 		Weight float64 `json:"weight"`
 	} `json:"additionall"`
+	Recipes []RecipeServing `json:"recipes"`
+	// Meal is the meal all saved foods were eaten at, optional
+	Meal string `json:"meal"`
+}
+
+type FoodSearchResult struct {
+	ID            uint    `json:"id"`
+	Name          string  `json:"name"`
+	Score         float64 `json:"score"`
+	Weight        float64 `json:"weight"`
+	Calories      float64 `json:"calories"`
+	Protein       float64 `json:"protein"`
+	Sugar         float64 `json:"sugar"`
+	Carbohydrates float64 `json:"carbohydrates"`
+	Fat           float64 `json:"fat"`
 }
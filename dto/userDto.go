@@ -69,7 +69,15 @@ type DailyProgressResponse struct {
 		Message       string        `json:"message"`
 		Satisfication Satisfication `json:"satisfication"`
 	} `json:"status"`
-	User UserRespStruct `json:"user"`
+	User            UserRespStruct       `json:"user"`
+	CaloriesBalance DailyCaloriesBalance `json:"caloriesBalance"`
+}
+
+// DailyCaloriesBalance is the calorie intake minus calories burned by activities
+type DailyCaloriesBalance struct {
+	Intake      float64 `json:"intake"`
+	Burned      float64 `json:"burned"`
+	NetCalories float64 `json:"netCalories"`
 }
 
 type UserRespStruct struct {
@@ -74,3 +74,123 @@ func ErrRefreshTokenExpired() error {
 func ErrInvalidPassword() error {
 	return errors.New("invalid password")
 }
+
+func ErrActivityNotFound() error {
+	return errors.New("activity not found")
+}
+
+func ErrExerciseNotFound() error {
+	return errors.New("exercise not found")
+}
+
+func ErrActivityInFuture() error {
+	return errors.New("performed_at cannot be in the future")
+}
+
+func ErrInvalidDate() error {
+	return errors.New("invalid date format, use YYYY-MM-DD")
+}
+
+func ErrHealthProfileRequired() error {
+	return errors.New("please create your health profile first")
+}
+
+func ErrMealPlanItemNotFound() error {
+	return errors.New("meal plan item not found")
+}
+
+func ErrFoodNotFound() error {
+	return errors.New("food not found")
+}
+
+func ErrNoMealCandidate() error {
+	return errors.New("no food available for this meal")
+}
+
+func ErrInvalidDietaryPreference() error {
+	return errors.New("invalid dietary preference")
+}
+
+func ErrInvalidFeedback() error {
+	return errors.New("invalid feedback, type must be food or exercise and rating like, dislike or none")
+}
+
+func ErrSearchQueryRequired() error {
+	return errors.New("search query must have at least 2 characters")
+}
+
+func ErrScanSessionNotFound() error {
+	return errors.New("scan session not found")
+}
+
+func ErrInvalidImage() error {
+	return errors.New("image must be a valid jpg, png or webp file")
+}
+
+func ErrImageTooLarge() error {
+	return errors.New("image must not be larger than 10 MB or 20 megapixels")
+}
+
+func ErrNoExerciseRecommendation() error {
+	return errors.New("no exercise recommendation available")
+}
+
+func ErrScanInProgress() error {
+	return errors.New("scan is still being processed")
+}
+
+func ErrScanFailed() error {
+	return errors.New("failed to scan food, please try again")
+}
+
+func ErrScanQueueFull() error {
+	return errors.New("too many scans are being processed, please try again later")
+}
+
+func ErrInvalidBarcode() error {
+	return errors.New("barcode must be a valid EAN or UPC code")
+}
+
+func ErrProductNotFound() error {
+	return errors.New("product not found")
+}
+
+func ErrInvalidCustomFood() error {
+	return errors.New("custom food needs a name, non negative nutrition and a serving weight when nutrition is per serving")
+}
+
+func ErrCustomFoodNotFound() error {
+	return errors.New("custom food not found")
+}
+
+func ErrCustomFoodExists() error {
+	return errors.New("you already have a custom food with this name")
+}
+
+func ErrInvalidRecipe() error {
+	return errors.New("recipe needs a name, at least one ingredient with a positive weight and a positive number of servings")
+}
+
+func ErrRecipeNotFound() error {
+	return errors.New("recipe not found")
+}
+
+func ErrRecipeExists() error {
+	return errors.New("you already have a food or recipe with this name")
+}
+
+func ErrInvalidMeal() error {
+	return errors.New("meal must be breakfast, lunch, dinner or snack")
+}
+
+func ErrNoFoodEntries() error {
+	return errors.New("no food entries found to copy")
+}
+
+func ErrCopySameDate() error {
+	return errors.New("can not copy food entries to the same date")
+}
+
+func ErrRecipePrivateIngredient() error {
+	return errors.New("a shared recipe can not contain private foods")
+}
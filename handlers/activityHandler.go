@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	helper "github.com/rizkirmdhnnn/sweetlife-backend-go/helpers"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/services"
+)
+
+type ActivityHandler struct {
+	activityService services.ActivityService
+}
+
+func NewActivityHandler(activityService services.ActivityService) *ActivityHandler {
+	if activityService == nil {
+		panic("activityService cannot be nil")
+	}
+	return &ActivityHandler{
+		activityService: activityService,
+	}
+}
+
+// LogActivity is a handler to log an exercise the user did
+func (h *ActivityHandler) LogActivity(c *gin.Context) {
+	// get userID from context
+	userID := c.GetString("userID")
+
+	// get data from request
+	var req dto.CreateActivityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	// call service to log activity
+	activity, err := h.activityService.LogActivity(userID, &req)
+	if err != nil {
+		switch err.Error() {
+		case errors.ErrExerciseNotFound().Error():
+			errors.SendErrorResponse(c, http.StatusNotFound, "Failed to log activity", err.Error())
+		case errors.ErrHealthProfileRequired().Error(), errors.ErrActivityInFuture().Error():
+			errors.SendErrorResponse(c, http.StatusBadRequest, "Failed to log activity", err.Error())
+		default:
+			errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to log activity", err.Error())
+		}
+		return
+	}
+
+	// give success response
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Activity logged successfully",
+		"data":    activity,
+	})
+}
+
+// GetActivities is a handler to get the activities of a day
+func (h *ActivityHandler) GetActivities(c *gin.Context) {
+	// get userID from context
+	userID := c.GetString("userID")
+
+	// call service to get activities
+	activities, err := h.activityService.GetActivities(userID, c.Query("date"))
+	if err != nil {
+		if err.Error() == errors.ErrInvalidDate().Error() {
+			errors.SendErrorResponse(c, http.StatusBadRequest, "Failed to get activities", err.Error())
+			return
+		}
+		errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get activities", err.Error())
+		return
+	}
+
+	// give success response
+	c.JSON(http.StatusOK, gin.H{
+		"status": true,
+		"data":   activities,
+	})
+}
+
+// DeleteActivity is a handler to delete an activity
+func (h *ActivityHandler) DeleteActivity(c *gin.Context) {
+	// get userID from context
+	userID := c.GetString("userID")
+
+	// parse activity id
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", "id must be a valid integer")
+		return
+	}
+
+	// call service to delete activity
+	if err := h.activityService.DeleteActivity(userID, uint(id)); err != nil {
+		if err.Error() == errors.ErrActivityNotFound().Error() {
+			errors.SendErrorResponse(c, http.StatusNotFound, "Failed to delete activity", err.Error())
+			return
+		}
+		errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to delete activity", err.Error())
+		return
+	}
+
+	// give success response
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Activity deleted successfully",
+	})
+}
+
+// GetExercises is a handler to get the exercise catalog
+func (h *ActivityHandler) GetExercises(c *gin.Context) {
+	// get language from query or header
+	lang := helper.PreferredLanguage(c.DefaultQuery("lang", c.GetHeader("Accept-Language")))
+
+	// call service to get exercise catalog
+	exercises, err := h.activityService.GetExerciseCatalog(lang)
+	if err != nil {
+		errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get exercises", err.Error())
+		return
+	}
+
+	// give success response
+	c.JSON(http.StatusOK, gin.H{
+		"status": true,
+		"data":   exercises,
+	})
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/services"
+)
+
+type CustomFoodHandler struct {
+	customFoodService services.CustomFoodService
+}
+
+func NewCustomFoodHandler(customFoodService services.CustomFoodService) *CustomFoodHandler {
+	if customFoodService == nil {
+		panic("customFoodService cannot be nil")
+	}
+	return &CustomFoodHandler{
+		customFoodService: customFoodService,
+	}
+}
+
+// CreateCustomFood is a handler to create a custom food
+func (h *CustomFoodHandler) CreateCustomFood(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req dto.CustomFoodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	// call service to create custom food
+	food, err := h.customFoodService.CreateCustomFood(userID, &req)
+	if err != nil {
+		sendCustomFoodError(c, "Failed to create custom food", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  true,
+		"message": "Custom food created successfully",
+		"data":    food,
+	})
+}
+
+// GetCustomFoods is a handler to get the custom foods of a user
+func (h *CustomFoodHandler) GetCustomFoods(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	// call service to get custom foods
+	foods, err := h.customFoodService.GetCustomFoods(userID)
+	if err != nil {
+		errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get custom foods", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": true,
+		"data":   foods,
+	})
+}
+
+// UpdateCustomFood is a handler to update a custom food
+func (h *CustomFoodHandler) UpdateCustomFood(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	// parse food id
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", "id must be a valid integer")
+		return
+	}
+
+	var req dto.CustomFoodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	// call service to update custom food
+	food, err := h.customFoodService.UpdateCustomFood(userID, uint(id), &req)
+	if err != nil {
+		sendCustomFoodError(c, "Failed to update custom food", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Custom food updated successfully",
+		"data":    food,
+	})
+}
+
+// sendCustomFoodError sends the error response of a failed custom food request
+func sendCustomFoodError(c *gin.Context, message string, err error) {
+	switch err.Error() {
+	case errors.ErrInvalidCustomFood().Error():
+		errors.SendErrorResponse(c, http.StatusBadRequest, message, err.Error())
+	case errors.ErrCustomFoodExists().Error():
+		errors.SendErrorResponse(c, http.StatusConflict, message, err.Error())
+	case errors.ErrCustomFoodNotFound().Error():
+		errors.SendErrorResponse(c, http.StatusNotFound, message, err.Error())
+	default:
+		errors.SendErrorResponse(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	helper "github.com/rizkirmdhnnn/sweetlife-backend-go/helpers"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/services"
+)
+
+type ExercisePlanHandler struct {
+	exercisePlanService services.ExercisePlanService
+}
+
+func NewExercisePlanHandler(exercisePlanService services.ExercisePlanService) *ExercisePlanHandler {
+	if exercisePlanService == nil {
+		panic("exercisePlanService cannot be nil")
+	}
+	return &ExercisePlanHandler{
+		exercisePlanService: exercisePlanService,
+	}
+}
+
+// GetPlan is a handler to get the weekly exercise plan
+func (h *ExercisePlanHandler) GetPlan(c *gin.Context) {
+	// get userID from context
+	userID := c.GetString("userID")
+	lang := helper.PreferredLanguage(c.DefaultQuery("lang", c.GetHeader("Accept-Language")))
+
+	// call service to get plan
+	plan, err := h.exercisePlanService.GetPlan(userID, lang)
+	if err != nil {
+		if err.Error() == errors.ErrHealthProfileRequired().Error() {
+			errors.SendErrorResponse(c, http.StatusBadRequest, "Failed to get exercise plan", err.Error())
+			return
+		}
+		if err.Error() == errors.ErrNoExerciseRecommendation().Error() {
+			errors.SendErrorResponse(c, http.StatusNotFound, "Failed to get exercise plan", err.Error())
+			return
+		}
+		errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get exercise plan", err.Error())
+		return
+	}
+
+	// give success response
+	c.JSON(http.StatusOK, gin.H{
+		"status": true,
+		"data":   plan,
+	})
+}
+
+// RegeneratePlan is a handler to regenerate the weekly exercise plan
+func (h *ExercisePlanHandler) RegeneratePlan(c *gin.Context) {
+	// get userID from context
+	userID := c.GetString("userID")
+	lang := helper.PreferredLanguage(c.DefaultQuery("lang", c.GetHeader("Accept-Language")))
+
+	// call service to regenerate plan
+	plan, err := h.exercisePlanService.RegeneratePlan(userID, lang)
+	if err != nil {
+		if err.Error() == errors.ErrHealthProfileRequired().Error() {
+			errors.SendErrorResponse(c, http.StatusBadRequest, "Failed to regenerate exercise plan", err.Error())
+			return
+		}
+		if err.Error() == errors.ErrNoExerciseRecommendation().Error() {
+			errors.SendErrorResponse(c, http.StatusNotFound, "Failed to regenerate exercise plan", err.Error())
+			return
+		}
+		errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to regenerate exercise plan", err.Error())
+		return
+	}
+
+	// give success response
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Exercise plan regenerated successfully",
+		"data":    plan,
+	})
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/services"
+)
+
+type FoodLogHandler struct {
+	foodLogService services.FoodLogService
+}
+
+func NewFoodLogHandler(foodLogService services.FoodLogService) *FoodLogHandler {
+	if foodLogService == nil {
+		panic("foodLogService cannot be nil")
+	}
+	return &FoodLogHandler{
+		foodLogService: foodLogService,
+	}
+}
+
+// GetFrequentFoods is a handler to get the pinned and most often logged foods of a user
+func (h *FoodLogHandler) GetFrequentFoods(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	// parse limit parameter
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		errors.SendErrorResponse(c, http.StatusBadRequest, "invalid limit parameter", "limit must be a valid integer")
+		return
+	}
+
+	// call service to get frequent foods
+	foods, err := h.foodLogService.GetFrequentFoods(userID, limit)
+	if err != nil {
+		errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get frequent foods", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": true,
+		"data":   foods,
+	})
+}
+
+// AddFavorite is a handler to pin a food
+func (h *FoodLogHandler) AddFavorite(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req dto.FavoriteFoodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	// call service to pin food
+	if err := h.foodLogService.AddFavorite(userID, req.FoodID); err != nil {
+		if err.Error() == errors.ErrFoodNotFound().Error() {
+			errors.SendErrorResponse(c, http.StatusNotFound, "Failed to pin food", err.Error())
+			return
+		}
+		errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to pin food", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Food pinned successfully",
+	})
+}
+
+// RemoveFavorite is a handler to unpin a food
+func (h *FoodLogHandler) RemoveFavorite(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	// parse food id
+	foodID, err := strconv.ParseUint(c.Param("food_id"), 10, 64)
+	if err != nil {
+		errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", "food_id must be a valid integer")
+		return
+	}
+
+	// call service to unpin food
+	if err := h.foodLogService.RemoveFavorite(userID, uint(foodID)); err != nil {
+		errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to unpin food", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Food unpinned successfully",
+	})
+}
+
+// QuickLog is a handler to log a food again with one tap
+func (h *FoodLogHandler) QuickLog(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req dto.QuickLogRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	// call service to log food
+	entry, err := h.foodLogService.QuickLog(userID, &req)
+	if err != nil {
+		if err.Error() == errors.ErrFoodNotFound().Error() {
+			errors.SendErrorResponse(c, http.StatusNotFound, "Failed to log food", err.Error())
+			return
+		}
+		if err.Error() == errors.ErrInvalidMeal().Error() {
+			errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+			return
+		}
+		errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to log food", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Food saved successfully",
+		"data":    entry,
+	})
+}
+
+// CopyFoodLog is a handler to copy the foods of a day or a meal to another day
+func (h *FoodLogHandler) CopyFoodLog(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req dto.CopyFoodLogRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	// call service to copy foods
+	entries, err := h.foodLogService.CopyFoodLog(userID, &req)
+	if err != nil {
+		switch err.Error() {
+		case errors.ErrInvalidDate().Error(), errors.ErrInvalidMeal().Error(), errors.ErrCopySameDate().Error():
+			errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		case errors.ErrNoFoodEntries().Error():
+			errors.SendErrorResponse(c, http.StatusNotFound, "Failed to copy foods", err.Error())
+		default:
+			errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to copy foods", err.Error())
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Foods copied successfully",
+		"data":    entries,
+	})
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	helper "github.com/rizkirmdhnnn/sweetlife-backend-go/helpers"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/services"
+)
+
+type MealPlanHandler struct {
+	mealPlanService services.MealPlanService
+}
+
+func NewMealPlanHandler(mealPlanService services.MealPlanService) *MealPlanHandler {
+	if mealPlanService == nil {
+		panic("mealPlanService cannot be nil")
+	}
+	return &MealPlanHandler{
+		mealPlanService: mealPlanService,
+	}
+}
+
+// GetMealPlan is a handler to get the meal plan of a day
+func (h *MealPlanHandler) GetMealPlan(c *gin.Context) {
+	// get userID from context
+	userID := c.GetString("userID")
+
+	// parse date, default to today
+	date := time.Now()
+	if value := c.Query("date"); value != "" {
+		parsed, err := helper.ParsedDate(value)
+		if err != nil {
+			errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", "invalid date format, use YYYY-MM-DD")
+			return
+		}
+		date = parsed
+	}
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+
+	// call service to get meal plan
+	plan, err := h.mealPlanService.GetMealPlan(userID, date)
+	if err != nil {
+		switch err.Error() {
+		case errors.ErrHealthProfileRequired().Error():
+			errors.SendErrorResponse(c, http.StatusBadRequest, "Failed to get meal plan", err.Error())
+		case errors.ErrNoMealCandidate().Error():
+			errors.SendErrorResponse(c, http.StatusNotFound, "Failed to get meal plan", err.Error())
+		default:
+			errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get meal plan", err.Error())
+		}
+		return
+	}
+
+	// give success response
+	c.JSON(http.StatusOK, gin.H{
+		"status": true,
+		"data":   plan,
+	})
+}
+
+// SwapItem is a handler to replace a single item of a meal plan
+func (h *MealPlanHandler) SwapItem(c *gin.Context) {
+	// get userID from context
+	userID := c.GetString("userID")
+
+	// parse item id
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", "id must be a valid integer")
+		return
+	}
+
+	// body is optional, without food_id the next best food is used
+	var req dto.SwapMealPlanItemRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+			return
+		}
+	}
+
+	// call service to swap item
+	plan, err := h.mealPlanService.SwapItem(userID, uint(id), &req)
+	if err != nil {
+		switch err.Error() {
+		case errors.ErrMealPlanItemNotFound().Error(), errors.ErrFoodNotFound().Error(), errors.ErrNoMealCandidate().Error():
+			errors.SendErrorResponse(c, http.StatusNotFound, "Failed to swap meal plan item", err.Error())
+		default:
+			errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to swap meal plan item", err.Error())
+		}
+		return
+	}
+
+	// give success response
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Meal plan item swapped successfully",
+		"data":    plan,
+	})
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/services"
+)
+
+type RecipeHandler struct {
+	recipeService services.RecipeService
+}
+
+func NewRecipeHandler(recipeService services.RecipeService) *RecipeHandler {
+	if recipeService == nil {
+		panic("recipeService cannot be nil")
+	}
+	return &RecipeHandler{
+		recipeService: recipeService,
+	}
+}
+
+// CreateRecipe is a handler to create a recipe
+func (h *RecipeHandler) CreateRecipe(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req dto.RecipeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	// call service to create recipe
+	recipe, err := h.recipeService.CreateRecipe(userID, &req)
+	if err != nil {
+		sendRecipeError(c, "Failed to create recipe", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  true,
+		"message": "Recipe created successfully",
+		"data":    recipe,
+	})
+}
+
+// GetRecipes is a handler to get the recipes of a user
+func (h *RecipeHandler) GetRecipes(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	// call service to get recipes
+	recipes, err := h.recipeService.GetRecipes(userID)
+	if err != nil {
+		errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get recipes", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": true,
+		"data":   recipes,
+	})
+}
+
+// GetRecipe is a handler to get a recipe with its nutrition
+func (h *RecipeHandler) GetRecipe(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	// parse recipe id
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", "id must be a valid integer")
+		return
+	}
+
+	// call service to get recipe
+	recipe, err := h.recipeService.GetRecipe(userID, uint(id))
+	if err != nil {
+		sendRecipeError(c, "Failed to get recipe", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": true,
+		"data":   recipe,
+	})
+}
+
+// UpdateRecipe is a handler to update a recipe
+func (h *RecipeHandler) UpdateRecipe(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	// parse recipe id
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", "id must be a valid integer")
+		return
+	}
+
+	var req dto.RecipeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	// call service to update recipe
+	recipe, err := h.recipeService.UpdateRecipe(userID, uint(id), &req)
+	if err != nil {
+		sendRecipeError(c, "Failed to update recipe", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Recipe updated successfully",
+		"data":    recipe,
+	})
+}
+
+// sendRecipeError sends the error response of a failed recipe request
+func sendRecipeError(c *gin.Context, message string, err error) {
+	switch err.Error() {
+	case errors.ErrInvalidRecipe().Error(), errors.ErrRecipePrivateIngredient().Error():
+		errors.SendErrorResponse(c, http.StatusBadRequest, message, err.Error())
+	case errors.ErrRecipeExists().Error():
+		errors.SendErrorResponse(c, http.StatusConflict, message, err.Error())
+	case errors.ErrRecipeNotFound().Error(), errors.ErrFoodNotFound().Error():
+		errors.SendErrorResponse(c, http.StatusNotFound, message, err.Error())
+	default:
+		errors.SendErrorResponse(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	helper "github.com/rizkirmdhnnn/sweetlife-backend-go/helpers"
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/services"
 )
 
@@ -31,8 +32,11 @@ func (r *RecomendationHandler) GetRecomendations(c *gin.Context) {
 		return
 	}
 
+	// get language from query or header
+	lang := helper.PreferredLanguage(c.DefaultQuery("lang", c.GetHeader("Accept-Language")))
+
 	// get exercise recomendations
-	exerciseRecomendations, err := r.recomendationService.GetExerciseRecomendations(userID)
+	exerciseRecomendations, err := r.recomendationService.GetExerciseRecomendations(userID, lang)
 	if err != nil {
 		errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get recomendations", err.Error())
 		return
@@ -49,3 +53,63 @@ func (r *RecomendationHandler) GetRecomendations(c *gin.Context) {
 	})
 
 }
+
+// SaveFeedback is a handler to like or dislike a recommended food or exercise
+func (r *RecomendationHandler) SaveFeedback(c *gin.Context) {
+	// get userID from context
+	userID := c.GetString("userID")
+
+	// get data from request
+	var req dto.RecommendationFeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	// call service to save feedback
+	if err := r.recomendationService.SaveFeedback(userID, &req); err != nil {
+		switch err.Error() {
+		case errors.ErrInvalidFeedback().Error():
+			errors.SendErrorResponse(c, http.StatusBadRequest, "Failed to save feedback", err.Error())
+		default:
+			errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to save feedback", err.Error())
+		}
+		return
+	}
+
+	// give success response
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Feedback saved successfully",
+	})
+}
+
+// AteFood is a handler to log a recommended food the user ate
+func (r *RecomendationHandler) AteFood(c *gin.Context) {
+	// get userID from context
+	userID := c.GetString("userID")
+
+	// get data from request
+	var req dto.AteFoodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	// call service to log the food
+	if err := r.recomendationService.AteFood(userID, &req); err != nil {
+		switch err.Error() {
+		case errors.ErrFoodNotFound().Error():
+			errors.SendErrorResponse(c, http.StatusNotFound, "Failed to save food", err.Error())
+		default:
+			errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to save food", err.Error())
+		}
+		return
+	}
+
+	// give success response
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Food saved successfully",
+	})
+}
@@ -1,14 +1,20 @@
 package handlers
 
 import (
+	stderrors "errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	helper "github.com/rizkirmdhnnn/sweetlife-backend-go/helpers"
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/services"
 )
 
+// maxScanRequestSize leaves room for the multipart headers next to the image
+const maxScanRequestSize = helper.MaxScanImageSize + 1<<20
+
 type ScanFoodHandler struct {
 	scanFoodService services.ScanFoodService
 }
@@ -22,37 +28,102 @@ func NewScanFoodHandler(scanFoodService services.ScanFoodService) *ScanFoodHandl
 // ScanFood is a handler to scan food
 func (s *ScanFoodHandler) ScanFood(c *gin.Context) {
 	// get file from request
+	// the body is limited before parsing, so a huge upload is not spooled to disk
+	// the image type is checked from its content by the service
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxScanRequestSize)
 	file, err := c.FormFile("image")
-
-	if file != nil {
-		if file.Header.Get("Content-Type") != "image/jpeg" && file.Header.Get("Content-Type") != "image/png" {
-			errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", "Profile picture must be in jpg or png format")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if stderrors.As(err, &tooLarge) {
+			sendScanError(c, errors.ErrImageTooLarge())
 			return
 		}
+		errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
 	}
 
-	if err != nil {
-		errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+	// get userID from context
+	userID := c.GetString("userID")
+
+	// in async mode the scan is processed in the background and polled with GetScan
+	if c.Query("async") == "true" {
+		job, err := s.scanFoodService.ScanFoodAsync(file, userID)
+		if err != nil {
+			sendScanError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusAccepted, gin.H{
+			"status":          true,
+			"message":         "Food scan queued successfully",
+			"scan_session_id": job.ScanSessionID,
+			"scan_status":     job.Status,
+		})
 		return
 	}
 
 	// call service to scan food
-	scanFoodResponse, err := s.scanFoodService.ScanFood(file)
+	scanFoodResponse, err := s.scanFoodService.ScanFood(file, userID)
 	if err != nil {
-		errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to scan food", err.Error())
+		sendScanError(c, err)
 		return
 	}
 
 	// give success response
 	c.JSON(http.StatusOK, gin.H{
-		"status":    true,
-		"message":   "Food detected successfully",
-		"food_list": scanFoodResponse.FoodList,
+		"status":          true,
+		"message":         "Food detected successfully",
+		"scan_session_id": scanFoodResponse.ScanSessionID,
+		"food_list":       scanFoodResponse.FoodList,
+		"unmatched":       scanFoodResponse.Unmatched,
 	})
 }
 
+// GetScan is a handler to get the status and result of a scan
+func (s *ScanFoodHandler) GetScan(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	// parse scan session id
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", "id must be a valid integer")
+		return
+	}
+
+	// call service to get scan
+	job, err := s.scanFoodService.GetScan(uint(id), userID)
+	if err != nil {
+		if err.Error() == errors.ErrScanSessionNotFound().Error() {
+			errors.SendErrorResponse(c, http.StatusNotFound, "Failed to get scan", err.Error())
+			return
+		}
+		errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get scan", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": true,
+		"data":   job,
+	})
+}
+
+// sendScanError sends the error response of a failed scan request
+func sendScanError(c *gin.Context, err error) {
+	switch err.Error() {
+	case errors.ErrInvalidImage().Error():
+		errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+	case errors.ErrImageTooLarge().Error():
+		errors.SendErrorResponse(c, http.StatusRequestEntityTooLarge, "Invalid request data", err.Error())
+	case errors.ErrScanQueueFull().Error():
+		errors.SendErrorResponse(c, http.StatusServiceUnavailable, "Failed to scan food", err.Error())
+	default:
+		errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to scan food", err.Error())
+	}
+}
+
 // SearchFood is a handler to search food
 func (s *ScanFoodHandler) FindFood(c *gin.Context) {
+	userID := c.GetString("userID")
 	var req dto.FindFoodRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
@@ -60,8 +131,12 @@ func (s *ScanFoodHandler) FindFood(c *gin.Context) {
 	}
 
 	// call searchFood service
-	food, err := s.scanFoodService.SearchFood(&req)
+	food, err := s.scanFoodService.SearchFood(&req, userID)
 	if err != nil {
+		if err.Error() == errors.ErrFoodNotFound().Error() {
+			errors.SendErrorResponse(c, http.StatusNotFound, "Failed to find food", err.Error())
+			return
+		}
 		errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
 		return
 	}
@@ -73,6 +148,90 @@ func (s *ScanFoodHandler) FindFood(c *gin.Context) {
 	})
 }
 
+// FindFoodByBarcode is a handler to find a packaged food by its barcode
+func (s *ScanFoodHandler) FindFoodByBarcode(c *gin.Context) {
+	var req dto.BarcodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	// call service to find product
+	food, err := s.scanFoodService.FindFoodByBarcode(&req)
+	if err != nil {
+		switch err.Error() {
+		case errors.ErrInvalidBarcode().Error():
+			errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		case errors.ErrProductNotFound().Error():
+			errors.SendErrorResponse(c, http.StatusNotFound, "Failed to find food", err.Error())
+		default:
+			errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to find food", err.Error())
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Food found successfully",
+		"data":    food,
+	})
+}
+
+// SearchFoodCandidates is a handler to search foods by name for autocomplete
+func (s *ScanFoodHandler) SearchFoodCandidates(c *gin.Context) {
+	// parse limit parameter
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		errors.SendErrorResponse(c, http.StatusBadRequest, "invalid limit parameter", "limit must be a valid integer")
+		return
+	}
+
+	// call service to search foods
+	foods, err := s.scanFoodService.SearchFoodCandidates(c.Query("q"), limit, c.GetString("userID"))
+	if err != nil {
+		if err.Error() == errors.ErrSearchQueryRequired().Error() {
+			errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+			return
+		}
+		errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to search food", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": true,
+		"data":   foods,
+	})
+}
+
+// SearchUSDAFoods is a handler to list the USDA foods the user can pick for a food missing from the database
+func (s *ScanFoodHandler) SearchUSDAFoods(c *gin.Context) {
+	// parse limit parameter
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		errors.SendErrorResponse(c, http.StatusBadRequest, "invalid limit parameter", "limit must be a valid integer")
+		return
+	}
+
+	// call service to search USDA foods
+	foods, err := s.scanFoodService.SearchUSDAFoods(c.Query("q"), limit)
+	if err != nil {
+		switch err.Error() {
+		case errors.ErrSearchQueryRequired().Error():
+			errors.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		case errors.ErrFoodNotFound().Error():
+			errors.SendErrorResponse(c, http.StatusNotFound, "Failed to search food", err.Error())
+		default:
+			errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to search food", err.Error())
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": true,
+		"data":   foods,
+	})
+}
+
 // SaveFood is a handler to save food
 func (s *ScanFoodHandler) SaveFood(c *gin.Context) {
 	userID := c.GetString("userID")
@@ -85,6 +244,22 @@ func (s *ScanFoodHandler) SaveFood(c *gin.Context) {
 	// call saveFood service
 	err := s.scanFoodService.SaveFood(&req, userID)
 	if err != nil {
+		if err.Error() == errors.ErrScanSessionNotFound().Error() {
+			errors.SendErrorResponse(c, http.StatusNotFound, "Failed to save food", err.Error())
+			return
+		}
+		if err.Error() == errors.ErrScanInProgress().Error() {
+			errors.SendErrorResponse(c, http.StatusConflict, "Failed to save food", err.Error())
+			return
+		}
+		if err.Error() == errors.ErrRecipeNotFound().Error() {
+			errors.SendErrorResponse(c, http.StatusNotFound, "Failed to save food", err.Error())
+			return
+		}
+		if err.Error() == errors.ErrInvalidRecipe().Error() || err.Error() == errors.ErrInvalidMeal().Error() {
+			errors.SendErrorResponse(c, http.StatusBadRequest, "Failed to save food", err.Error())
+			return
+		}
 		errors.SendErrorResponse(c, http.StatusInternalServerError, "Failed to save food", err.Error())
 		return
 	}
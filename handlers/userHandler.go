@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	helper "github.com/rizkirmdhnnn/sweetlife-backend-go/helpers"
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/services"
 )
 
@@ -113,7 +114,10 @@ func (h *UserHandler) GetHistory(c *gin.Context) {
 	// get userID from context
 	userID := c.GetString("userID")
 
-	foodHistory, err := h.userService.GetFoodHistoryWithPagination(userID)
+	// get language from query or header
+	lang := helper.PreferredLanguage(c.DefaultQuery("lang", c.GetHeader("Accept-Language")))
+
+	foodHistory, err := h.userService.GetFoodHistoryWithPagination(userID, lang)
 	if err != nil {
 		errors.SendErrorResponse(c, http.StatusInternalServerError, "failed to get user history", err.Error())
 		return
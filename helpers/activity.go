@@ -0,0 +1,33 @@
+package helper
+
+import (
+	"errors"
+	"math"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+)
+
+// CalculateCaloriesBurned estimates burned calories from a MET value.
+// kcal = MET x intensity factor x body weight (kg) x duration (hours)
+// Source : https://pacompendium.com/
+func CalculateCaloriesBurned(met, weight float64, duration int, intensity models.ActivityIntensity) (float64, error) {
+	if met <= 0 || weight <= 0 || duration <= 0 {
+		return 0, errors.New("invalid input: met, weight, and duration must be positive numbers")
+	}
+
+	var factor float64
+	switch intensity {
+	case models.LightIntensity:
+		factor = 0.8
+	case models.ModerateIntensity:
+		factor = 1.0
+	case models.VigorousIntensity:
+		factor = 1.2
+	default:
+		return 0, errors.New("invalid intensity: must be light, moderate, or vigorous")
+	}
+
+	result := met * factor * weight * (float64(duration) / 60)
+	multiplier := math.Pow(10, 2)
+	return math.Round(result*multiplier) / multiplier, nil
+}
@@ -0,0 +1,59 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+)
+
+func TestCalculateCaloriesBurned(t *testing.T) {
+	tests := []struct {
+		name      string
+		met       float64
+		weight    float64
+		duration  int
+		intensity models.ActivityIntensity
+		want      float64
+	}{
+		{"moderate", 8, 70, 30, models.ModerateIntensity, 280},
+		{"light", 8, 70, 30, models.LightIntensity, 224},
+		{"vigorous", 8, 70, 30, models.VigorousIntensity, 336},
+		{"rounded to two decimals", 3.5, 65.3, 17, models.ModerateIntensity, 64.76},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CalculateCaloriesBurned(tt.met, tt.weight, tt.duration, tt.intensity)
+			if err != nil {
+				t.Fatalf("CalculateCaloriesBurned() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CalculateCaloriesBurned() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateCaloriesBurnedInvalidInput(t *testing.T) {
+	tests := []struct {
+		name      string
+		met       float64
+		weight    float64
+		duration  int
+		intensity models.ActivityIntensity
+	}{
+		{"zero met", 0, 70, 30, models.ModerateIntensity},
+		{"negative weight", 8, -70, 30, models.ModerateIntensity},
+		{"zero duration", 8, 70, 0, models.ModerateIntensity},
+		{"unknown intensity", 8, 70, 30, "extreme"},
+		{"empty intensity", 8, 70, 30, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CalculateCaloriesBurned(tt.met, tt.weight, tt.duration, tt.intensity); err == nil {
+				t.Error("CalculateCaloriesBurned() error = nil, want an error")
+			}
+		})
+	}
+}
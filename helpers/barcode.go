@@ -0,0 +1,48 @@
+package helper
+
+import (
+	"strings"
+)
+
+// NormalizeBarcode validates an EAN-8, UPC-A, EAN-13 or GTIN-14 code by its check digit.
+// UPC-A and GTIN-14 codes are converted to EAN-13 when possible, so a product is stored
+// and looked up with the same code whatever symbology it was scanned from.
+func NormalizeBarcode(code string) (string, bool) {
+	code = strings.TrimSpace(code)
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return "", false
+		}
+	}
+
+	switch len(code) {
+	case 8, 13:
+	case 12:
+		code = "0" + code
+	case 14:
+		if code[0] == '0' {
+			code = code[1:]
+		}
+	default:
+		return "", false
+	}
+
+	if !validCheckDigit(code) {
+		return "", false
+	}
+	return code, true
+}
+
+// validCheckDigit checks the GS1 check digit, the last digit of the code
+func validCheckDigit(code string) bool {
+	sum := 0
+	// digits are weighted 3 and 1 alternately from the right, skipping the check digit
+	for i := len(code) - 2; i >= 0; i-- {
+		digit := int(code[i] - '0')
+		if (len(code)-2-i)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return (10-sum%10)%10 == int(code[len(code)-1]-'0')
+}
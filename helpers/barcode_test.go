@@ -0,0 +1,33 @@
+package helper
+
+import "testing"
+
+func TestNormalizeBarcode(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want string
+		ok   bool
+	}{
+		{"EAN-13", "4006381333931", "4006381333931", true},
+		{"EAN-8", "96385074", "96385074", true},
+		{"UPC-A is converted to EAN-13", "036000291452", "0036000291452", true},
+		{"GTIN-14 is converted to EAN-13", "00036000291452", "0036000291452", true},
+		{"GTIN-14 with packaging indicator is kept", "10036000291459", "10036000291459", true},
+		{"surrounding spaces are trimmed", " 4006381333931 ", "4006381333931", true},
+		{"wrong check digit", "4006381333932", "", false},
+		{"letters", "40063813339AB", "", false},
+		{"inner space", "4006381 333931", "", false},
+		{"unsupported length", "123456789", "", false},
+		{"empty", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NormalizeBarcode(tt.code)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("NormalizeBarcode(%q) = %q, %t, want %q, %t", tt.code, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
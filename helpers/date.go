@@ -12,3 +12,14 @@ func ParsedDate(value string) (time.Time, error) {
 	}
 	return parsedDate, nil
 }
+
+// StartOfDay returns midnight of the given time in its location
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// StartOfWeek returns midnight of the Monday of the given time's week
+func StartOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7 // Monday = 0
+	return StartOfDay(t).AddDate(0, 0, -offset)
+}
@@ -0,0 +1,95 @@
+package helper
+
+import (
+	"strings"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+)
+
+// dietaryExcludedTags lists the food tags a dietary preference does not allow.
+var dietaryExcludedTags = map[models.DietaryPreference][]string{
+	models.Vegetarian:     {"meat", "poultry", "pork", "fish", "seafood"},
+	models.Vegan:          {"meat", "poultry", "pork", "fish", "seafood", "dairy", "egg", "honey"},
+	models.Halal:          {"pork", "alcohol"},
+	models.LactoseFree:    {"dairy"},
+	models.GlutenFree:     {"gluten"},
+	models.NutAllergy:     {"nuts", "peanut"},
+	models.SeafoodAllergy: {"fish", "seafood"},
+	models.EggAllergy:     {"egg"},
+}
+
+// dietaryCompatibleTags lists the food tags confirming a food fits a dietary preference.
+var dietaryCompatibleTags = map[models.DietaryPreference][]string{
+	models.Vegetarian:     {"vegetarian", "vegan"},
+	models.Vegan:          {"vegan"},
+	models.Halal:          {"halal"},
+	models.LactoseFree:    {"lactose_free", "vegan"},
+	models.GlutenFree:     {"gluten_free"},
+	models.NutAllergy:     {"nut_free"},
+	models.SeafoodAllergy: {"seafood_free"},
+	models.EggAllergy:     {"egg_free", "vegan"},
+}
+
+// dietaryUnknownAllowed lists the preferences a food without excluded or compatible tags
+// is allowed for. A wrong food is only an inconvenience for them, while a vegetarian or
+// an allergic user must not get a food nothing is known about.
+var dietaryUnknownAllowed = map[models.DietaryPreference]bool{
+	models.Halal:       true,
+	models.LactoseFree: true,
+}
+
+// IsValidDietaryPreference reports whether the preference is supported.
+func IsValidDietaryPreference(preference models.DietaryPreference) bool {
+	_, ok := dietaryExcludedTags[preference]
+	return ok
+}
+
+// IsFoodAllowed reports whether a food with the given tags fits all dietary preferences.
+// A food with an excluded tag never fits, a food with neither excluded nor compatible tags
+// only fits the preferences in dietaryUnknownAllowed.
+func IsFoodAllowed(tags []string, preferences []models.DietaryPreference) bool {
+	for _, preference := range preferences {
+		if hasAnyTag(tags, dietaryExcludedTags[preference]) {
+			return false
+		}
+		if !dietaryUnknownAllowed[preference] && !hasAnyTag(tags, dietaryCompatibleTags[preference]) {
+			return false
+		}
+	}
+	return true
+}
+
+// DietaryScore counts the dietary preferences a food is confirmed to fit.
+// It is used to rank tagged foods above foods nothing is known about.
+func DietaryScore(tags []string, preferences []models.DietaryPreference) int {
+	score := 0
+	for _, preference := range preferences {
+		if hasAnyTag(tags, dietaryCompatibleTags[preference]) {
+			score++
+		}
+	}
+	return score
+}
+
+// IsKnownFoodTag reports whether a tag is used by any dietary preference.
+func IsKnownFoodTag(tag string) bool {
+	for _, tags := range []map[models.DietaryPreference][]string{dietaryExcludedTags, dietaryCompatibleTags} {
+		for _, known := range tags {
+			if hasAnyTag([]string{tag}, known) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasAnyTag(tags []string, wanted []string) bool {
+	for _, tag := range tags {
+		for _, w := range wanted {
+			if strings.EqualFold(tag, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+)
+
+func TestIsFoodAllowed(t *testing.T) {
+	tests := []struct {
+		name        string
+		tags        []string
+		preferences []models.DietaryPreference
+		want        bool
+	}{
+		{"no preferences", nil, nil, true},
+		{"vegetarian food", []string{"vegetarian", "egg"}, []models.DietaryPreference{models.Vegetarian}, true},
+		{"vegan food for a vegetarian", []string{"vegan"}, []models.DietaryPreference{models.Vegetarian}, true},
+		{"meat for a vegetarian", []string{"poultry", "halal"}, []models.DietaryPreference{models.Vegetarian}, false},
+		{"untagged food for a vegetarian", nil, []models.DietaryPreference{models.Vegetarian}, false},
+		{"egg for a vegan", []string{"vegetarian", "egg"}, []models.DietaryPreference{models.Vegan}, false},
+		{"untagged food for halal", nil, []models.DietaryPreference{models.Halal}, true},
+		{"pork for halal", []string{"pork"}, []models.DietaryPreference{models.Halal}, false},
+		{"untagged food for lactose free", nil, []models.DietaryPreference{models.LactoseFree}, true},
+		{"untagged food for gluten free", nil, []models.DietaryPreference{models.GlutenFree}, false},
+		{"nut free food for a nut allergy", []string{"nut_free"}, []models.DietaryPreference{models.NutAllergy}, true},
+		{"peanut for a nut allergy", []string{"peanut", "vegetarian"}, []models.DietaryPreference{models.NutAllergy}, false},
+		{"food not known to be nut free for a nut allergy", []string{"vegan"}, []models.DietaryPreference{models.NutAllergy}, false},
+		{"all preferences must fit", []string{"vegan", "nut_free"}, []models.DietaryPreference{models.Vegan, models.NutAllergy, models.Halal}, true},
+		{"one preference does not fit", []string{"vegan"}, []models.DietaryPreference{models.Vegan, models.SeafoodAllergy}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsFoodAllowed(tt.tags, tt.preferences); got != tt.want {
+				t.Errorf("IsFoodAllowed(%v, %v) = %t, want %t", tt.tags, tt.preferences, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDietaryScore(t *testing.T) {
+	preferences := []models.DietaryPreference{models.Halal, models.EggAllergy}
+
+	if got := DietaryScore([]string{"vegan", "halal"}, preferences); got != 2 {
+		t.Errorf("DietaryScore() = %d, want 2", got)
+	}
+	if got := DietaryScore(nil, preferences); got != 0 {
+		t.Errorf("DietaryScore() of an untagged food = %d, want 0", got)
+	}
+}
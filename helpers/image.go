@@ -0,0 +1,189 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+
+	apperrors "github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+// Limits of uploaded scan images
+const (
+	MaxScanImageSize      = 10 << 20
+	MaxScanImageDimension = 1280
+	// MaxScanImagePixels bounds the decoded size, a small file can declare a huge image
+	MaxScanImagePixels = 20_000_000
+	scanImageQuality   = 85
+)
+
+// PrepareScanImage validates an uploaded image and re-encodes it as JPEG.
+// The content type is sniffed from the bytes, so the file name and headers do not matter.
+// Re-encoding drops all metadata, including EXIF GPS data, and large images are
+// downscaled so the longest side is at most MaxScanImageDimension.
+func PrepareScanImage(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxScanImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxScanImageSize {
+		return nil, apperrors.ErrImageTooLarge()
+	}
+
+	var decode func(io.Reader) (image.Image, error)
+	var decodeConfig func(io.Reader) (image.Config, error)
+	orientation := 1
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		decode, decodeConfig = jpeg.Decode, jpeg.DecodeConfig
+		orientation = jpegOrientation(data)
+	case "image/png":
+		decode, decodeConfig = png.Decode, png.DecodeConfig
+	case "image/webp":
+		decode, decodeConfig = webp.Decode, webp.DecodeConfig
+	default:
+		return nil, apperrors.ErrInvalidImage()
+	}
+
+	// check the dimensions before decoding allocates the whole image
+	config, err := decodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width <= 0 || config.Height <= 0 {
+		return nil, apperrors.ErrInvalidImage()
+	}
+	if int64(config.Width)*int64(config.Height) > MaxScanImagePixels {
+		return nil, apperrors.ErrImageTooLarge()
+	}
+
+	img, err := decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, apperrors.ErrInvalidImage()
+	}
+
+	// downscale first, rotating the small image is cheap
+	img = orientImage(resizeImage(img, MaxScanImageDimension), orientation)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: scanImageQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// resizeImage downscales an image so its longest side is at most maxDimension
+func resizeImage(img image.Image, maxDimension int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxDimension && height <= maxDimension {
+		return img
+	}
+
+	if width >= height {
+		height = height * maxDimension / width
+		width = maxDimension
+	} else {
+		width = width * maxDimension / height
+		height = maxDimension
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, max(width, 1), max(height, 1)))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// orientImage applies an EXIF orientation, since the orientation tag is dropped on re-encoding
+func orientImage(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	// orientations 5 to 8 swap width and height
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirrored horizontally
+				dx, dy = width-1-x, y
+			case 3: // rotated 180
+				dx, dy = width-1-x, height-1-y
+			case 4: // mirrored vertically
+				dx, dy = x, height-1-y
+			case 5: // mirrored and rotated 90 counter clockwise
+				dx, dy = y, x
+			case 6: // rotated 90 clockwise
+				dx, dy = height-1-y, x
+			case 7: // mirrored and rotated 90 clockwise
+				dx, dy = height-1-y, width-1-x
+			case 8: // rotated 90 counter clockwise
+				dx, dy = y, width-1-x
+			}
+			dst.Set(dx, dy, img.At(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// jpegOrientation reads the EXIF orientation tag of a JPEG, 1 means no transformation
+func jpegOrientation(data []byte) int {
+	// walk the JPEG segments until the EXIF APP1 segment
+	pos := 2
+	for pos+4 <= len(data) && data[pos] == 0xFF {
+		marker := data[pos+1]
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if marker == 0xDA || length < 2 || pos+2+length > len(data) {
+			return 1
+		}
+		segment := data[pos+4 : pos+2+length]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(segment[6:])
+		}
+		pos += 2 + length
+	}
+	return 1
+}
+
+// tiffOrientation reads the orientation tag from the first IFD of EXIF TIFF data
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+	return 1
+}
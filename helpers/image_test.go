@@ -0,0 +1,133 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	apperrors "github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+)
+
+func newTestImage(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+	return img
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatalf("encode jpeg: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// withOrientation adds an EXIF segment with the orientation tag after the JPEG start marker
+func withOrientation(data []byte, orientation uint16) []byte {
+	tiff := []byte{'M', 'M', 0, 42, 0, 0, 0, 8, 0, 1}
+	entry := make([]byte, 12)
+	binary.BigEndian.PutUint16(entry[0:], 0x0112)
+	binary.BigEndian.PutUint16(entry[2:], 3) // SHORT
+	binary.BigEndian.PutUint32(entry[4:], 1)
+	binary.BigEndian.PutUint16(entry[8:], orientation)
+	tiff = append(tiff, entry...)
+	tiff = append(tiff, 0, 0, 0, 0)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, app1...)
+	return append(out, data[2:]...)
+}
+
+// withDimensions rewrites the size in the IHDR chunk of a PNG
+func withDimensions(data []byte, width, height uint32) []byte {
+	out := append([]byte{}, data...)
+	// signature, chunk length and type come before the IHDR data
+	binary.BigEndian.PutUint32(out[16:], width)
+	binary.BigEndian.PutUint32(out[20:], height)
+	binary.BigEndian.PutUint32(out[29:], crc32.ChecksumIEEE(out[12:29]))
+	return out
+}
+
+func decodeSize(t *testing.T, data []byte) (int, int) {
+	t.Helper()
+	config, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("result is not a jpeg: %v", err)
+	}
+	return config.Width, config.Height
+}
+
+func TestPrepareScanImage(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       func(t *testing.T) []byte
+		wantWidth  int
+		wantHeight int
+	}{
+		{"small jpeg is kept", func(t *testing.T) []byte { return encodeJPEG(t, newTestImage(64, 48)) }, 64, 48},
+		{"png is converted", func(t *testing.T) []byte { return encodePNG(t, newTestImage(30, 20)) }, 30, 20},
+		{"large image is downscaled", func(t *testing.T) []byte { return encodePNG(t, newTestImage(2560, 1280)) }, 1280, 640},
+		{"EXIF rotation is applied", func(t *testing.T) []byte { return withOrientation(encodeJPEG(t, newTestImage(40, 20)), 6) }, 20, 40},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := PrepareScanImage(bytes.NewReader(tt.data(t)))
+			if err != nil {
+				t.Fatalf("PrepareScanImage() error = %v", err)
+			}
+			if width, height := decodeSize(t, out); width != tt.wantWidth || height != tt.wantHeight {
+				t.Errorf("size = %dx%d, want %dx%d", width, height, tt.wantWidth, tt.wantHeight)
+			}
+			if jpegOrientation(out) != 1 {
+				t.Error("result still has an EXIF orientation")
+			}
+		})
+	}
+}
+
+func TestPrepareScanImageRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    func(t *testing.T) []byte
+		wantErr error
+	}{
+		{"not an image", func(t *testing.T) []byte { return []byte("GIF89a not supported") }, apperrors.ErrInvalidImage()},
+		{"truncated jpeg", func(t *testing.T) []byte { return encodeJPEG(t, newTestImage(64, 48))[:100] }, apperrors.ErrInvalidImage()},
+		{"file too large", func(t *testing.T) []byte { return make([]byte, MaxScanImageSize+1) }, apperrors.ErrImageTooLarge()},
+		{"too many pixels", func(t *testing.T) []byte { return withDimensions(encodePNG(t, newTestImage(1, 1)), 5000, 5000) }, apperrors.ErrImageTooLarge()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := PrepareScanImage(bytes.NewReader(tt.data(t)))
+			if err == nil || err.Error() != tt.wantErr.Error() {
+				t.Errorf("PrepareScanImage() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
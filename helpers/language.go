@@ -0,0 +1,24 @@
+package helper
+
+import "strings"
+
+// Supported languages
+const (
+	LangEnglish    = "en"
+	LangIndonesian = "id"
+)
+
+// PreferredLanguage returns the supported language of an Accept-Language header
+// or lang query value, defaulting to English.
+func PreferredLanguage(value string) string {
+	for _, part := range strings.Split(value, ",") {
+		tag := strings.ToLower(strings.TrimSpace(strings.SplitN(part, ";", 2)[0]))
+		switch {
+		case strings.HasPrefix(tag, "id"), strings.HasPrefix(tag, "in"):
+			return LangIndonesian
+		case strings.HasPrefix(tag, "en"):
+			return LangEnglish
+		}
+	}
+	return LangEnglish
+}
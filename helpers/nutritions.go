@@ -8,9 +8,19 @@ import (
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
 )
 
+// NutritionWeight returns the weight the nutrition values are given for, 100 g when it is not known.
+// It is also the weight of one unit of the food.
+func NutritionWeight(nutrisi *models.FoodNutrition) float64 {
+	if nutrisi.Weight <= 0 {
+		return 100
+	}
+	return nutrisi.Weight
+}
+
 // CalculateNutrients menghitung nilai nutrisi berdasarkan berat baru.
+// Nilai nutrisi dihitung dari berat nutrisi, atau 100 g jika beratnya tidak diketahui.
 func CalculateNutrients(newWeight float64, nutrisi *models.FoodNutrition) models.FoodNutrition {
-	ratio := newWeight / 100
+	ratio := newWeight / NutritionWeight(nutrisi)
 
 	return models.FoodNutrition{
 		Calories:      nutrisi.Calories * ratio,
@@ -0,0 +1,36 @@
+package helper
+
+import (
+	"math"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+)
+
+// Portion sizes of a scanned food
+const (
+	PortionSmall  = "small"
+	PortionMedium = "medium"
+	PortionLarge  = "large"
+)
+
+// portionFactors scale the catalog weight of a food, a medium portion is the catalog weight
+var portionFactors = []struct {
+	Size   string
+	Factor float64
+}{
+	{PortionSmall, 0.75},
+	{PortionMedium, 1},
+	{PortionLarge, 1.5},
+}
+
+// PortionOptions returns the weight of one unit of a food for every portion size.
+func PortionOptions(baseWeight float64) []dto.PortionOption {
+	options := make([]dto.PortionOption, 0, len(portionFactors))
+	for _, portion := range portionFactors {
+		options = append(options, dto.PortionOption{
+			Size:   portion.Size,
+			Weight: math.Round(baseWeight*portion.Factor*100) / 100,
+		})
+	}
+	return options
+}
@@ -1,7 +1,14 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/config"
@@ -10,10 +17,11 @@ import (
 )
 
 func main() {
-	// Load environment variables, database, and storage
+	// Load environment variables, database, storage, and cache
 	config.LoadEnv()
 	config.LoadDatabase()
 	config.LoadStorageBucket()
+	config.LoadCache()
 
 	// Initialize Gin router
 	router := gin.Default()
@@ -26,6 +34,28 @@ func main() {
 	routers.Routers(router)
 
 	// Log and start the server
-	log.Println("Server started on port", config.ENV.APP_PORT)
-	router.Run(":" + config.ENV.APP_PORT)
+	server := &http.Server{
+		Addr:    ":" + config.ENV.APP_PORT,
+		Handler: router,
+	}
+	go func() {
+		log.Println("Server started on port", config.ENV.APP_PORT)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	// Stop accepting requests on SIGINT or SIGTERM, then finish the queued scans
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	log.Println("Shutting down server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Failed to shut down server: %v", err)
+	}
+	routers.Shutdown()
+	log.Println("Server stopped")
 }
@@ -0,0 +1,160 @@
+package mlclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+)
+
+// ML service endpoints
+const (
+	EndpointScanFood               = "/scan-food"
+	EndpointDiabetesPredict        = "/diabetes_predict"
+	EndpointFoodRecommendation     = "/food_recommendation"
+	EndpointExerciseRecommendation = "/exercise_recommendation"
+)
+
+// DefaultTimeout is used for endpoints without a specific timeout.
+const DefaultTimeout = 15 * time.Second
+
+// maxLoggedBody limits how much of a response body is kept in errors.
+const maxLoggedBody = 512
+
+// Client is a typed client for the SweetLife ML service.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	timeouts   map[string]time.Duration
+}
+
+// NewClient creates a new ML service client for the given base URL.
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: httpClient,
+		timeouts: map[string]time.Duration{
+			EndpointScanFood:               30 * time.Second,
+			EndpointDiabetesPredict:        10 * time.Second,
+			EndpointFoodRecommendation:     15 * time.Second,
+			EndpointExerciseRecommendation: 10 * time.Second,
+		},
+	}
+}
+
+// BaseURL returns the base URL the client points at.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// SetTimeout overrides the timeout of a single endpoint.
+func (c *Client) SetTimeout(endpoint string, timeout time.Duration) {
+	c.timeouts[endpoint] = timeout
+}
+
+// ScanFood sends an image URL to the ML service and returns the detected foods.
+func (c *Client) ScanFood(ctx context.Context, imageURL string) (*dto.ScanFoodClientResp, error) {
+	var resp dto.ScanFoodClientResp
+	if err := c.post(ctx, EndpointScanFood, map[string]interface{}{"image": imageURL}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DiabetesPrediction predicts the diabetes risk of a user.
+func (c *Client) DiabetesPrediction(ctx context.Context, data *dto.DiabetesPredictionRequest) (*dto.DiabetesPredictionClientResp, error) {
+	var resp dto.DiabetesPredictionClientResp
+	if err := c.post(ctx, EndpointDiabetesPredict, data, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// FoodRecommendation returns food recommendations for a diabetes risk percentage.
+func (c *Client) FoodRecommendation(ctx context.Context, diabetesPercentage float32) (*dto.FoodRecomendationClientResp, error) {
+	var resp dto.FoodRecomendationClientResp
+	body := map[string]interface{}{"diabetes_percentage": diabetesPercentage}
+	if err := c.post(ctx, EndpointFoodRecommendation, body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ExerciseRecommendation returns exercise recommendations for a user.
+func (c *Client) ExerciseRecommendation(ctx context.Context, data *dto.ExerciseRequest) (*dto.ExerciseRecommendationClientResp, error) {
+	var resp dto.ExerciseRecommendationClientResp
+	if err := c.post(ctx, EndpointExerciseRecommendation, data, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// post sends a JSON request to the endpoint and decodes the JSON response into out.
+func (c *Client) post(ctx context.Context, endpoint string, in interface{}, out interface{}) error {
+	payload, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("%s: failed to encode request: %w", endpoint, err)
+	}
+
+	timeout, ok := c.timeouts[endpoint]
+	if !ok {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return &Error{Endpoint: endpoint, Kind: ErrRequestFailed, Err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// bodies are not logged, requests contain health data of the user
+	start := time.Now()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		log.Printf("mlclient: POST %s failed after %s: %v", endpoint, time.Since(start), err)
+		if errors.Is(err, context.DeadlineExceeded) {
+			return &Error{Endpoint: endpoint, Kind: ErrTimeout, Err: err}
+		}
+		return &Error{Endpoint: endpoint, Kind: ErrRequestFailed, Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &Error{Endpoint: endpoint, StatusCode: resp.StatusCode, Kind: ErrInvalidResponse, Err: err}
+	}
+
+	log.Printf("mlclient: POST %s status=%d duration=%s request_bytes=%d response_bytes=%d", endpoint, resp.StatusCode, time.Since(start), len(payload), len(body))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &Error{Endpoint: endpoint, StatusCode: resp.StatusCode, Body: string(truncate(body)), Kind: ErrUnexpectedStatus}
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return &Error{Endpoint: endpoint, StatusCode: resp.StatusCode, Body: string(truncate(body)), Kind: ErrInvalidResponse, Err: err}
+	}
+
+	return nil
+}
+
+// truncate shortens a body so it can be kept in an error.
+func truncate(body []byte) []byte {
+	if len(body) > maxLoggedBody {
+		return append(body[:maxLoggedBody:maxLoggedBody], "..."...)
+	}
+	return body
+}
@@ -0,0 +1,77 @@
+package mlclient
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+var (
+	// ErrRequestFailed is returned when the request could not reach the ML service.
+	ErrRequestFailed = errors.New("ml service request failed")
+	// ErrTimeout is returned when the ML service did not answer within the endpoint timeout.
+	ErrTimeout = errors.New("ml service request timed out")
+	// ErrUnexpectedStatus is returned when the ML service answers with a non 2xx status code.
+	ErrUnexpectedStatus = errors.New("ml service returned unexpected status")
+	// ErrInvalidResponse is returned when the ML service response cannot be decoded.
+	ErrInvalidResponse = errors.New("ml service returned invalid response")
+)
+
+// Error describes a failed call to an ML service endpoint.
+// Use errors.Is with the sentinel errors above to check the kind of failure.
+type Error struct {
+	Endpoint   string
+	StatusCode int
+	Body       string
+	Kind       error
+	Err        error
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%s: %s", e.Endpoint, e.Kind)
+	if e.StatusCode != 0 {
+		msg = fmt.Sprintf("%s (status %d)", msg, e.StatusCode)
+	}
+	if e.Err != nil {
+		msg = fmt.Sprintf("%s: %v", msg, e.Err)
+	}
+	return msg
+}
+
+// Is reports whether target is the kind of this error.
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// IsRetryable reports whether err is a transient failure worth retrying:
+// network errors, timeouts, rate limiting and 5xx responses.
+func IsRetryable(err error) bool {
+	var mlErr *Error
+	if !errors.As(err, &mlErr) {
+		return false
+	}
+	switch mlErr.Kind {
+	case ErrRequestFailed, ErrTimeout:
+		return true
+	case ErrUnexpectedStatus:
+		return mlErr.StatusCode == 429 || mlErr.StatusCode >= 500
+	}
+	return false
+}
+
+// IsConnectionError reports whether err happened before the request reached the
+// ML service, so even a non idempotent request can safely be sent again.
+func IsConnectionError(err error) bool {
+	var mlErr *Error
+	if !errors.As(err, &mlErr) || mlErr.Kind != ErrRequestFailed {
+		return false
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
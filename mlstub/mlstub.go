@@ -0,0 +1,154 @@
+// Package mlstub implements a local stand-in for the SweetLife ML service.
+// Every endpoint answers with deterministic canned data so the backend can be
+// run offline and exercised in integration tests.
+package mlstub
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/mlclient"
+)
+
+// NewHandler returns an http.Handler serving the ML service endpoints.
+func NewHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(mlclient.EndpointScanFood, post(scanFood))
+	mux.HandleFunc(mlclient.EndpointDiabetesPredict, post(diabetesPredict))
+	mux.HandleFunc(mlclient.EndpointFoodRecommendation, post(foodRecommendation))
+	mux.HandleFunc(mlclient.EndpointExerciseRecommendation, post(exerciseRecommendation))
+	return mux
+}
+
+// NewServer starts an in-process stub server. Callers must Close it when done
+// and can point an mlclient.Client at its URL.
+func NewServer() *httptest.Server {
+	return httptest.NewServer(NewHandler())
+}
+
+// post only allows POST requests with a JSON body.
+func post(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+		next(w, r)
+	}
+}
+
+func scanFood(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Image string `json:"image"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Image == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "image is required"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dto.ScanFoodClientResp{
+		Objects: []dto.ScanFood{
+			{Name: "Nasi", Unit: 1},
+			{Name: "Ayam", Unit: 1},
+			{Name: "Tempe", Unit: 2},
+			{Name: "Sayur", Unit: 1},
+		},
+	})
+}
+
+func diabetesPredict(w http.ResponseWriter, r *http.Request) {
+	var req dto.DiabetesPredictionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	// simple additive score so the same input always gives the same result
+	percentage := 10.0
+	if req.BMI > 25 {
+		percentage += (req.BMI - 25) * 3
+	}
+	if req.Age > 40 {
+		percentage += float64(req.Age-40) * 0.8
+	}
+	if req.HeartDisease {
+		percentage += 15
+	}
+	switch req.SmokingHistory {
+	case "current":
+		percentage += 10
+	case "former", "ever":
+		percentage += 5
+	}
+	percentage = math.Round(math.Min(percentage, 95)*100) / 100
+
+	note := "Your diabetes risk is low, keep up your healthy lifestyle."
+	switch {
+	case percentage > 70:
+		note = "Your diabetes risk is high, please consult a doctor."
+	case percentage > 50:
+		note = "Your diabetes risk is moderate, consider improving your diet and activity."
+	}
+
+	writeJSON(w, http.StatusOK, dto.DiabetesPredictionClientResp{
+		Percentage: percentage,
+		Note:       note,
+	})
+}
+
+func foodRecommendation(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		DiabetesPercentage float32 `json:"diabetes_percentage"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dto.FoodRecomendationClientResp{
+		Diabetes: req.DiabetesPercentage > 50,
+		FoodRecomendation: [][]dto.FoodClientResp{
+			{
+				{Name: "Gado-gado", Calories: 295, Carbohydrate: 22.5, Fat: 18.2, Proteins: 12.1, Image: "https://storage.googleapis.com/sweetlife-go-new/website/food/gado-gado.jpg"},
+				{Name: "Pepes Ikan", Calories: 180, Carbohydrate: 3.4, Fat: 8.6, Proteins: 22.3, Image: "https://storage.googleapis.com/sweetlife-go-new/website/food/pepes-ikan.jpg"},
+				{Name: "Sayur Bening", Calories: 65, Carbohydrate: 10.2, Fat: 0.8, Proteins: 3.1, Image: "https://storage.googleapis.com/sweetlife-go-new/website/food/sayur-bening.jpg"},
+			},
+			{
+				{Name: "Tahu Bacem", Calories: 160, Carbohydrate: 12.8, Fat: 8.1, Proteins: 10.4, Image: "https://storage.googleapis.com/sweetlife-go-new/website/food/tahu-bacem.jpg"},
+				{Name: "Ayam Bakar", Calories: 240, Carbohydrate: 4.6, Fat: 11.9, Proteins: 28.7, Image: "https://storage.googleapis.com/sweetlife-go-new/website/food/ayam-bakar.jpg"},
+			},
+		},
+	})
+}
+
+func exerciseRecommendation(w http.ResponseWriter, r *http.Request) {
+	var req dto.ExerciseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	resp := dto.ExerciseRecommendationClientResp{
+		CaloriesBurned:     250,
+		ExerciseCategories: []string{"Running", "Squats", "Yoga"},
+		ExerciseDuration:   30,
+	}
+	if req.Diabetes || req.Bmi >= 25 {
+		resp = dto.ExerciseRecommendationClientResp{
+			CaloriesBurned:     200,
+			ExerciseCategories: []string{"Brisk walking", "Cycling", "Swimming"},
+			ExerciseDuration:   45,
+		}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
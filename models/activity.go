@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type ActivityIntensity string
+
+const (
+	LightIntensity    ActivityIntensity = "light"
+	ModerateIntensity ActivityIntensity = "moderate"
+	VigorousIntensity ActivityIntensity = "vigorous"
+)
+
+// ActivityLog is an exercise a user actually did.
+// CaloriesBurned is estimated from the exercise MET value and the user's weight.
+type ActivityLog struct {
+	ID             uint              `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID         string            `json:"user_id" gorm:"type:uuid;not null;index"`
+	User           User              `json:"-" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	ExerciseID     uint              `json:"exercise_id" gorm:"not null;index"`
+	Exercise       Exercise          `json:"exercise" gorm:"foreignKey:ExerciseID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+	Duration       int               `json:"duration" gorm:"not null"`
+	Intensity      ActivityIntensity `json:"intensity" gorm:"type:varchar(10);not null"`
+	CaloriesBurned float64           `json:"calories_burned" gorm:"not null;type:decimal(7,2)"`
+	PerformedAt    time.Time         `json:"performed_at" gorm:"not null;index"`
+	CreatedAt      time.Time         `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time         `json:"updated_at" gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Exercise is an entry of the exercise catalog used for recommendations.
+// MET (metabolic equivalent of task) is used to estimate burned calories.
+type Exercise struct {
+	ID              uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name            string    `json:"name" gorm:"type:varchar(100);not null;uniqueIndex"`
+	Description     string    `json:"description" gorm:"type:text;not null"`
+	DescriptionIndo string    `json:"description_indo" gorm:"type:text"`
+	MET             float64   `json:"met" gorm:"not null;type:decimal(4,2)"`
+	Image           string    `json:"image" gorm:"type:text"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
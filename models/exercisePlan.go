@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// ExercisePlan is a weekly exercise plan generated from the ML exercise recommendation.
+// ProfileUpdatedAt keeps the health profile version the plan was generated from,
+// so the plan can be regenerated when the profile changes.
+// CaloriesBurned is the target of the whole week, each session targets an equal share of it.
+type ExercisePlan struct {
+	ID               uint                  `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID           string                `json:"user_id" gorm:"type:uuid;not null;uniqueIndex"`
+	User             User                  `json:"-" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	WeekStart        time.Time             `json:"week_start" gorm:"type:date;not null"`
+	CaloriesBurned   float64               `json:"calories_burned" gorm:"not null;type:decimal(7,2)"`
+	SessionDuration  int                   `json:"session_duration" gorm:"not null"`
+	ProfileUpdatedAt time.Time             `json:"profile_updated_at" gorm:"not null"`
+	Sessions         []ExercisePlanSession `json:"sessions" gorm:"foreignKey:PlanID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	CreatedAt        time.Time             `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time             `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// ExercisePlanSession is a single planned exercise session of an ExercisePlan.
+type ExercisePlanSession struct {
+	ID             uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	PlanID         uint      `json:"plan_id" gorm:"not null;index"`
+	Date           time.Time `json:"date" gorm:"type:date;not null"`
+	ExerciseID     *uint     `json:"exercise_id" gorm:"index"`
+	Exercise       *Exercise `json:"exercise,omitempty" gorm:"foreignKey:ExerciseID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	ExerciseName   string    `json:"exercise_name" gorm:"type:varchar(100);not null"`
+	Duration       int       `json:"duration" gorm:"not null"`
+	TargetCalories float64   `json:"target_calories" gorm:"not null;type:decimal(7,2)"`
+	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// FavoriteFood is a food pinned by a user to the top of the frequent foods
+type FavoriteFood struct {
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    string    `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_favorite_foods_user_food"`
+	User      User      `json:"-" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	FoodID    uint      `json:"food_id" gorm:"not null;uniqueIndex:idx_favorite_foods_user_food"`
+	Food      Food      `json:"-" gorm:"foreignKey:FoodID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// FoodLogStat is how often and when a user last logged a food
+type FoodLogStat struct {
+	FoodID       uint
+	Count        int
+	LastLoggedAt time.Time
+}
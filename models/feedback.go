@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+type FeedbackTarget string
+
+const (
+	FoodFeedback     FeedbackTarget = "food"
+	ExerciseFeedback FeedbackTarget = "exercise"
+)
+
+type FeedbackRating string
+
+const (
+	LikeRating    FeedbackRating = "like"
+	DislikeRating FeedbackRating = "dislike"
+)
+
+// RecommendationFeedback is the rating a user gave to a recommended food or exercise.
+// Recommendations come from the ML service by name, so feedback is stored by name as well.
+type RecommendationFeedback struct {
+	ID         uint           `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID     string         `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_recommendation_feedback"`
+	User       User           `json:"-" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	TargetType FeedbackTarget `json:"target_type" gorm:"type:varchar(10);not null;uniqueIndex:idx_recommendation_feedback"`
+	Name       string         `json:"name" gorm:"type:varchar(255);not null;uniqueIndex:idx_recommendation_feedback"`
+	Rating     FeedbackRating `json:"rating" gorm:"type:varchar(10);not null"`
+	CreatedAt  time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+}
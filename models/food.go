@@ -4,13 +4,60 @@ import (
 	"time"
 )
 
+// FoodVisibility controls who can see a custom food
+type FoodVisibility string
+
+const (
+	FoodVisibilityPublic  FoodVisibility = "public"
+	FoodVisibilityPrivate FoodVisibility = "private"
+)
+
 type Food struct {
+	ID   uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name string `gorm:"type:varchar(255);not null" json:"name"`
+	// OwnerID is the user that created a custom food, catalog foods have no owner.
+	// Private foods are only visible to their owner.
+	OwnerID    *string        `gorm:"type:uuid;index" json:"owner_id,omitempty"`
+	Owner      *User          `json:"-" gorm:"foreignKey:OwnerID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Visibility FoodVisibility `gorm:"type:varchar(10);not null;default:'public';index" json:"visibility"`
+	FdcID      *int           `gorm:"uniqueIndex" json:"fdc_id,omitempty"` // USDA food the food was imported from
+	Tags       []FoodTag      `gorm:"many2many:food_tag_relations;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"tags,omitempty"`
+	Aliases    []FoodAlias    `gorm:"foreignKey:FoodID" json:"aliases,omitempty"`
+	CreatedAt  time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// FoodAlias is another name of a food, e.g. "Ayam" or "ayam goreng" for "Chicken".
+// Aliases are unique regardless of case, so a name always resolves to the same food.
+type FoodAlias struct {
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	FoodID    uint      `gorm:"not null;index" json:"food_id"`
+	Food      Food      `json:"-" gorm:"foreignKey:FoodID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Language  string    `gorm:"type:varchar(5);not null" json:"language"`
+	Alias     string    `gorm:"type:varchar(255);not null" json:"alias"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// FoodTag describes an ingredient or property of a food, e.g. "dairy" or "halal".
+// Tags are used to match foods with the dietary preferences of a user.
+type FoodTag struct {
 	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
-	Name      string    `gorm:"type:varchar(255);not null" json:"name"`
+	Name      string    `gorm:"type:varchar(50);not null;uniqueIndex" json:"name"`
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
+// FoodNameTag tags a food by its lower case name. It covers the dishes recommended by the
+// ML service, they are only added to the food table once a user eats them.
+type FoodNameTag struct {
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name      string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_food_name_tag" json:"name"`
+	TagID     uint      `gorm:"not null;uniqueIndex:idx_food_name_tag" json:"tag_id"`
+	Tag       FoodTag   `json:"-" gorm:"foreignKey:TagID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
 type FoodNutrition struct {
 	ID            uint      `json:"id" gorm:"primaryKey;autoIncrement"`
 	FoodID        uint      `gorm:"not null;index" json:"food_id"`
@@ -30,16 +77,38 @@ type FoodWithNutritions struct {
 	Nutrition FoodNutrition `json:"nutrition"`
 }
 
+// FoodSearchResult is a food matching a search query, a higher score is a better match.
+type FoodSearchResult struct {
+	FoodWithNutritions
+	Score float64 `json:"score"`
+}
+
 type UserFoodHistory struct {
-	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
-	UserID    string    `gorm:"not null;index" json:"user_id"`
-	User      User      `json:"user" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
-	FoodID    uint      `gorm:"not null;index" json:"food_id"`
-	Food      Food      `json:"food" gorm:"foreignKey:FoodID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
-	Unit      int       `gorm:"not null" json:"unit"`
-	Weight    *float64  `gorm:"default:null" json:"weight"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID     uint     `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID string   `gorm:"not null;index" json:"user_id"`
+	User   User     `json:"user" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	FoodID uint     `gorm:"not null;index" json:"food_id"`
+	Food   Food     `json:"food" gorm:"foreignKey:FoodID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Unit   int      `gorm:"not null" json:"unit"`
+	Weight *float64 `gorm:"default:null" json:"weight"`
+	// Meal is the meal the food was eaten at, when the user told
+	Meal *MealType `gorm:"type:varchar(10);index" json:"meal"`
+	// ScanSessionID links entries saved from a scan to the scan
+	ScanSessionID *uint        `gorm:"index" json:"scan_session_id"`
+	ScanSession   *ScanSession `json:"-" gorm:"foreignKey:ScanSessionID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	CreatedAt     time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// UnmatchedScanLabel is a label detected by the ML service that is not in the food catalog.
+// Count and LastSeenAt help the catalog team decide which foods to add first.
+type UnmatchedScanLabel struct {
+	ID         uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Label      string    `json:"label" gorm:"type:varchar(255);not null;uniqueIndex"`
+	Count      int       `json:"count" gorm:"not null;default:1"`
+	LastSeenAt time.Time `json:"last_seen_at" gorm:"not null"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
 type ScanFood struct {
@@ -29,6 +29,19 @@ const (
 	HighRisk   RiskLevelType = "high"
 )
 
+type DietaryPreference string
+
+const (
+	Vegetarian     DietaryPreference = "vegetarian"
+	Vegan          DietaryPreference = "vegan"
+	Halal          DietaryPreference = "halal"
+	LactoseFree    DietaryPreference = "lactose_free"
+	GlutenFree     DietaryPreference = "gluten_free"
+	NutAllergy     DietaryPreference = "nut_allergy"
+	SeafoodAllergy DietaryPreference = "seafood_allergy"
+	EggAllergy     DietaryPreference = "egg_allergy"
+)
+
 type SmokingHistory string
 
 const (
@@ -76,3 +89,11 @@ type RiskAssessment struct {
 	CreatedAt  time.Time        `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt  time.Time        `json:"updated_at" gorm:"autoUpdateTime"`
 }
+
+type UserDietaryPreference struct {
+	ID         uint              `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID     string            `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_user_dietary_preference"`
+	User       User              `json:"-" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Preference DietaryPreference `json:"preference" gorm:"type:varchar(30);not null;uniqueIndex:idx_user_dietary_preference"`
+	CreatedAt  time.Time         `json:"created_at" gorm:"autoCreateTime"`
+}
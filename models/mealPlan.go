@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+type MealType string
+
+const (
+	Breakfast MealType = "breakfast"
+	Lunch     MealType = "lunch"
+	Dinner    MealType = "dinner"
+	Snack     MealType = "snack"
+)
+
+// MealPlan is a generated plan for a single day. The targets are kept so the
+// plan still makes sense when the health profile changes later.
+type MealPlan struct {
+	ID             uint           `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID         string         `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_meal_plan_user_date"`
+	User           User           `json:"-" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Date           time.Time      `json:"date" gorm:"type:date;not null;uniqueIndex:idx_meal_plan_user_date"`
+	TargetCalories float64        `json:"target_calories" gorm:"not null;type:decimal(7,2)"`
+	TargetSugar    float64        `json:"target_sugar" gorm:"not null;type:decimal(7,2)"`
+	TargetCarbs    float64        `json:"target_carbs" gorm:"not null;type:decimal(7,2)"`
+	Items          []MealPlanItem `json:"items" gorm:"foreignKey:PlanID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	CreatedAt      time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// MealPlanItem is a food planned for a meal. Nutrition values are already
+// multiplied by Portion. FoodID is empty for ML recommendations that are not
+// in the local food table.
+type MealPlanItem struct {
+	ID            uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	PlanID        uint      `json:"plan_id" gorm:"not null;index"`
+	Meal          MealType  `json:"meal" gorm:"type:varchar(20);not null"`
+	FoodID        *uint     `json:"food_id" gorm:"index"`
+	Food          *Food     `json:"-" gorm:"foreignKey:FoodID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	Name          string    `json:"name" gorm:"type:varchar(255);not null"`
+	Image         string    `json:"image" gorm:"type:varchar(255)"`
+	Portion       float64   `json:"portion" gorm:"not null;type:decimal(4,2)"`
+	Weight        float64   `json:"weight" gorm:"not null"`
+	Calories      float64   `json:"calories" gorm:"not null"`
+	Sugar         float64   `json:"sugar" gorm:"not null"`
+	Carbohydrates float64   `json:"carbohydrates" gorm:"not null"`
+	Fat           float64   `json:"fat" gorm:"not null"`
+	Proteins      float64   `json:"proteins" gorm:"not null"`
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
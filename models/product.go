@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Product is a packaged food identified by its barcode, imported from Open Food Facts.
+// Nutrition is per 100 gram, FoodID is the food created for it the first time it is looked up.
+type Product struct {
+	ID            uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Barcode       string    `json:"barcode" gorm:"type:varchar(14);not null;uniqueIndex"`
+	Name          string    `json:"name" gorm:"type:varchar(255);not null"`
+	Brand         string    `json:"brand" gorm:"type:varchar(255)"`
+	ServingWeight float64   `json:"serving_weight"`
+	Calories      float64   `json:"calories" gorm:"not null"`
+	Sugar         float64   `json:"sugar" gorm:"not null"`
+	Fat           float64   `json:"fat" gorm:"not null"`
+	Carbohydrates float64   `json:"carbohydrates" gorm:"not null"`
+	Proteins      float64   `json:"proteins" gorm:"not null"`
+	FoodID        *uint     `json:"food_id" gorm:"index"`
+	Food          *Food     `json:"-" gorm:"foreignKey:FoodID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Recipe is a dish composed of foods. It is backed by a food holding the nutrition of one
+// serving, so a serving is searched, saved and counted like any other food. The food is
+// owned by the creator of the recipe and its visibility shares or hides the recipe.
+type Recipe struct {
+	ID          uint               `json:"id" gorm:"primaryKey;autoIncrement"`
+	FoodID      uint               `json:"food_id" gorm:"not null;uniqueIndex"`
+	Food        Food               `json:"food" gorm:"foreignKey:FoodID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	OwnerID     string             `json:"owner_id" gorm:"type:uuid;not null;index"`
+	Owner       User               `json:"-" gorm:"foreignKey:OwnerID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Servings    float64            `json:"servings" gorm:"not null"`
+	Ingredients []RecipeIngredient `json:"ingredients" gorm:"foreignKey:RecipeID"`
+	CreatedAt   time.Time          `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time          `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// RecipeIngredient is a food used in a recipe with its weight in grams
+type RecipeIngredient struct {
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	RecipeID  uint      `json:"recipe_id" gorm:"not null;index"`
+	Recipe    *Recipe   `json:"-" gorm:"foreignKey:RecipeID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	FoodID    uint      `json:"food_id" gorm:"not null;index"`
+	Food      Food      `json:"food" gorm:"foreignKey:FoodID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Weight    float64   `json:"weight" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
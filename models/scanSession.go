@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// ScanStatus is the processing state of a scan session.
+type ScanStatus string
+
+const (
+	ScanStatusPending    ScanStatus = "pending"
+	ScanStatusProcessing ScanStatus = "processing"
+	ScanStatusDone       ScanStatus = "done"
+	ScanStatusFailed     ScanStatus = "failed"
+)
+
+// ScanSession is a single food scan of a user. It keeps the uploaded image, what the ML
+// service detected, what was matched to the catalog and what the user finally saved,
+// as an audit trail and as training data for the ML team. The JSON columns hold
+// dto.ScanFood and dto.FoodList lists, Result holds the dto.ScanFoodResponse.
+// Async scans are processed in the background, Status tells whether the result is ready.
+type ScanSession struct {
+	ID             uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID         string     `json:"user_id" gorm:"type:uuid;not null;index"`
+	User           User       `json:"-" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Status         ScanStatus `json:"status" gorm:"type:varchar(12);not null;default:'done';index"`
+	Error          string     `json:"error" gorm:"type:text"`
+	ImageObject    string     `json:"image_object" gorm:"type:text;not null"`
+	ImageURL       string     `json:"image_url" gorm:"type:text;not null"`
+	RawObjects     string     `json:"raw_objects" gorm:"type:jsonb;not null;default:'[]'"`
+	MatchedItems   string     `json:"matched_items" gorm:"type:jsonb;not null;default:'[]'"`
+	Result         *string    `json:"result" gorm:"type:jsonb"`
+	ConfirmedItems *string    `json:"confirmed_items" gorm:"type:jsonb"`
+	SavedAt        *time.Time `json:"saved_at"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
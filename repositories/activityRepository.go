@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"gorm.io/gorm"
+)
+
+// ActivityRepository is a contract of activity log repository
+type ActivityRepository interface {
+	CreateActivity(activity *models.ActivityLog) error
+	GetActivityByID(id uint) (*models.ActivityLog, error)
+	GetActivitiesByDate(userID string, date time.Time) ([]models.ActivityLog, error)
+	GetActivitiesBetween(userID string, start, end time.Time) ([]models.ActivityLog, error)
+	GetCaloriesBurnedByDate(userID string, date time.Time) (float64, error)
+	DeleteActivity(activity *models.ActivityLog) error
+}
+
+type activityRepository struct {
+	db *gorm.DB
+}
+
+// NewActivityRepository is a constructor to create activity repository
+func NewActivityRepository(db *gorm.DB) ActivityRepository {
+	if db == nil {
+		panic("database connection cannot be nil")
+	}
+	return &activityRepository{
+		db: db,
+	}
+}
+
+// CreateActivity implements ActivityRepository.
+func (r *activityRepository) CreateActivity(activity *models.ActivityLog) error {
+	return r.db.Create(activity).Error
+}
+
+// GetActivityByID implements ActivityRepository.
+func (r *activityRepository) GetActivityByID(id uint) (*models.ActivityLog, error) {
+	var activity models.ActivityLog
+	if err := r.db.Preload("Exercise").First(&activity, id).Error; err != nil {
+		return nil, err
+	}
+	return &activity, nil
+}
+
+// GetActivitiesByDate implements ActivityRepository.
+func (r *activityRepository) GetActivitiesByDate(userID string, date time.Time) ([]models.ActivityLog, error) {
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	return r.GetActivitiesBetween(userID, start, start.AddDate(0, 0, 1))
+}
+
+// GetActivitiesBetween implements ActivityRepository.
+// start is inclusive and end is exclusive.
+func (r *activityRepository) GetActivitiesBetween(userID string, start, end time.Time) ([]models.ActivityLog, error) {
+	var activities []models.ActivityLog
+	err := r.db.Preload("Exercise").
+		Where("user_id = ? AND performed_at >= ? AND performed_at < ?", userID, start, end).
+		Order("performed_at DESC").
+		Find(&activities).Error
+	if err != nil {
+		return nil, err
+	}
+	return activities, nil
+}
+
+// GetCaloriesBurnedByDate implements ActivityRepository.
+func (r *activityRepository) GetCaloriesBurnedByDate(userID string, date time.Time) (float64, error) {
+	var total float64
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	err := r.db.Model(&models.ActivityLog{}).
+		Select("COALESCE(SUM(calories_burned), 0)").
+		Where("user_id = ? AND performed_at >= ? AND performed_at < ?", userID, start, start.AddDate(0, 0, 1)).
+		Scan(&total).Error
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// DeleteActivity implements ActivityRepository.
+func (r *activityRepository) DeleteActivity(activity *models.ActivityLog) error {
+	return r.db.Delete(activity).Error
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/cache"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+)
+
+// cachedRecomendationRepo caches ML recommendations. Keys contain the model
+// version, so deploying a new model invalidates every cached entry.
+type cachedRecomendationRepo struct {
+	next         RecomendationRepo
+	cache        cache.Cache
+	ttl          time.Duration
+	modelVersion string
+}
+
+// NewCachedRecomendationRepo wraps a RecomendationRepo with a cache.
+func NewCachedRecomendationRepo(next RecomendationRepo, cache cache.Cache, ttl time.Duration, modelVersion string) RecomendationRepo {
+	if next == nil {
+		panic("next cannot be nil")
+	}
+	if cache == nil {
+		panic("cache cannot be nil")
+	}
+	return &cachedRecomendationRepo{
+		next:         next,
+		cache:        cache,
+		ttl:          ttl,
+		modelVersion: modelVersion,
+	}
+}
+
+// RiskBucket rounds a diabetes risk percentage to the bucket used for caching.
+func RiskBucket(diabetPercentage float32) float32 {
+	return float32(math.Round(float64(diabetPercentage)))
+}
+
+// GetFoodRecomendations implements RecomendationRepo.
+// Food recommendations only depend on the risk score, so the ML service is
+// called with the rounded score and the result is shared by the whole bucket.
+func (c *cachedRecomendationRepo) GetFoodRecomendations(diabetPercentage float32) (*dto.FoodRecomendationClientResp, error) {
+	bucket := RiskBucket(diabetPercentage)
+	key := fmt.Sprintf("recommendation:%s:food:%.0f", c.modelVersion, bucket)
+
+	var resp dto.FoodRecomendationClientResp
+	if c.get(key, &resp) {
+		return &resp, nil
+	}
+
+	fresh, err := c.next.GetFoodRecomendations(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	c.set(key, fresh)
+	return fresh, nil
+}
+
+// GetExerciceRecomendations implements RecomendationRepo.
+// Height and BMI are rounded so nearly identical profiles share an entry.
+func (c *cachedRecomendationRepo) GetExerciceRecomendations(data *dto.ExerciseRequest) (*dto.ExerciseRecommendationClientResp, error) {
+	rounded := *data
+	rounded.Height = math.Round(data.Height)
+	rounded.Bmi = math.Round(data.Bmi*10) / 10
+	key := fmt.Sprintf("recommendation:%s:exercise:%s:%d:%.0f:%.1f:%t",
+		c.modelVersion, rounded.Gender, rounded.Age, rounded.Height, rounded.Bmi, rounded.Diabetes)
+
+	var resp dto.ExerciseRecommendationClientResp
+	if c.get(key, &resp) {
+		return &resp, nil
+	}
+
+	fresh, err := c.next.GetExerciceRecomendations(&rounded)
+	if err != nil {
+		return nil, err
+	}
+
+	c.set(key, fresh)
+	return fresh, nil
+}
+
+// DiabetesPrediction implements RecomendationRepo.
+func (c *cachedRecomendationRepo) DiabetesPrediction(data *dto.DiabetesPredictionRequest) (*dto.DiabetesPredictionClientResp, error) {
+	return c.next.DiabetesPrediction(data)
+}
+
+// get loads a cached value into out. Cache failures are logged and treated as a miss.
+func (c *cachedRecomendationRepo) get(key string, out interface{}) bool {
+	value, found, err := c.cache.Get(context.Background(), key)
+	if err != nil {
+		log.Printf("recommendation cache: failed to get %s: %v", key, err)
+		return false
+	}
+	if !found {
+		return false
+	}
+	if err := json.Unmarshal(value, out); err != nil {
+		log.Printf("recommendation cache: failed to decode %s: %v", key, err)
+		return false
+	}
+	return true
+}
+
+// set stores a value in the cache. Cache failures are logged and ignored.
+func (c *cachedRecomendationRepo) set(key string, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("recommendation cache: failed to encode %s: %v", key, err)
+		return
+	}
+	if err := c.cache.Set(context.Background(), key, data, c.ttl); err != nil {
+		log.Printf("recommendation cache: failed to set %s: %v", key, err)
+	}
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"strings"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// notRecipeFood leaves out the foods backing a recipe
+const notRecipeFood = "NOT EXISTS (SELECT 1 FROM recipes WHERE recipes.food_id = foods.id)"
+
+// CustomFoodRepository is a contract of custom food repository
+type CustomFoodRepository interface {
+	CreateCustomFood(food *models.Food, nutrition *models.FoodNutrition) error
+	UpdateCustomFood(food *models.Food, nutrition *models.FoodNutrition) error
+	GetCustomFoods(userID string) ([]models.FoodWithNutritions, error)
+	GetCustomFoodByID(id uint, userID string) (*models.FoodWithNutritions, error)
+	CustomFoodNameExists(userID string, name string, excludeID uint) (bool, error)
+}
+
+type customFoodRepository struct {
+	db *gorm.DB
+}
+
+// NewCustomFoodRepository is a constructor to create custom food repository
+func NewCustomFoodRepository(db *gorm.DB) CustomFoodRepository {
+	if db == nil {
+		panic("database connection cannot be nil")
+	}
+	return &customFoodRepository{
+		db: db,
+	}
+}
+
+// CreateCustomFood implements CustomFoodRepository.
+func (r *customFoodRepository) CreateCustomFood(food *models.Food, nutrition *models.FoodNutrition) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(food).Error; err != nil {
+			return err
+		}
+		nutrition.FoodID = food.ID
+		return tx.Create(nutrition).Error
+	})
+}
+
+// UpdateCustomFood implements CustomFoodRepository.
+func (r *customFoodRepository) UpdateCustomFood(food *models.Food, nutrition *models.FoodNutrition) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(food).Error; err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Save(nutrition).Error
+	})
+}
+
+// GetCustomFoods implements CustomFoodRepository.
+// Only the custom foods owned by the user are returned, newest first.
+// Foods of recipes are managed with their recipe and left out.
+func (r *customFoodRepository) GetCustomFoods(userID string) ([]models.FoodWithNutritions, error) {
+	var nutritions []models.FoodNutrition
+	err := r.db.Preload("Food").
+		Joins("JOIN foods ON foods.id = food_nutritions.food_id").
+		Where("foods.owner_id = ?", userID).
+		Where(notRecipeFood).
+		Order("foods.created_at DESC").
+		Find(&nutritions).Error
+	if err != nil {
+		return nil, err
+	}
+	return toFoodsWithNutritions(nutritions), nil
+}
+
+// GetCustomFoodByID implements CustomFoodRepository.
+// Custom foods of other users are not found, even when they are public.
+func (r *customFoodRepository) GetCustomFoodByID(id uint, userID string) (*models.FoodWithNutritions, error) {
+	var nutrition models.FoodNutrition
+	err := r.db.Preload("Food").
+		Joins("JOIN foods ON foods.id = food_nutritions.food_id").
+		Where("foods.id = ? AND foods.owner_id = ?", id, userID).
+		Where(notRecipeFood).
+		First(&nutrition).Error
+	if err != nil {
+		return nil, err
+	}
+	return &models.FoodWithNutritions{Food: nutrition.Food, Nutrition: nutrition}, nil
+}
+
+// CustomFoodNameExists implements CustomFoodRepository.
+func (r *customFoodRepository) CustomFoodNameExists(userID string, name string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Food{}).
+		Where("owner_id = ? AND LOWER(name) = ? AND id <> ?", userID, strings.ToLower(strings.TrimSpace(name)), excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
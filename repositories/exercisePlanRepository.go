@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"gorm.io/gorm"
+)
+
+// ExercisePlanRepository is a contract of exercise plan repository
+type ExercisePlanRepository interface {
+	GetPlanByUserID(userID string) (*models.ExercisePlan, error)
+	ReplacePlan(plan *models.ExercisePlan) error
+}
+
+type exercisePlanRepository struct {
+	db *gorm.DB
+}
+
+// NewExercisePlanRepository is a constructor to create exercise plan repository
+func NewExercisePlanRepository(db *gorm.DB) ExercisePlanRepository {
+	if db == nil {
+		panic("database connection cannot be nil")
+	}
+	return &exercisePlanRepository{
+		db: db,
+	}
+}
+
+// GetPlanByUserID implements ExercisePlanRepository.
+func (r *exercisePlanRepository) GetPlanByUserID(userID string) (*models.ExercisePlan, error) {
+	var plan models.ExercisePlan
+	err := r.db.
+		Preload("Sessions", func(db *gorm.DB) *gorm.DB { return db.Order("date, id") }).
+		Preload("Sessions.Exercise").
+		Where("user_id = ?", userID).
+		First(&plan).Error
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// ReplacePlan implements ExercisePlanRepository.
+// The user's previous plan and its sessions are removed in the same transaction.
+func (r *exercisePlanRepository) ReplacePlan(plan *models.ExercisePlan) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", plan.UserID).Delete(&models.ExercisePlan{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(plan).Error
+	})
+}
@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"strings"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"gorm.io/gorm"
+)
+
+// ExerciseRepository is a contract of exercise catalog repository
+type ExerciseRepository interface {
+	GetExercisesByNames(names []string) ([]models.Exercise, error)
+	GetExerciseByID(id uint) (*models.Exercise, error)
+	GetAllExercises() ([]models.Exercise, error)
+}
+
+type exerciseRepository struct {
+	db *gorm.DB
+}
+
+// NewExerciseRepository is a constructor to create exercise repository
+func NewExerciseRepository(db *gorm.DB) ExerciseRepository {
+	if db == nil {
+		panic("database connection cannot be nil")
+	}
+	return &exerciseRepository{
+		db: db,
+	}
+}
+
+// GetExercisesByNames implements ExerciseRepository.
+// Names are matched case-insensitively.
+func (r *exerciseRepository) GetExercisesByNames(names []string) ([]models.Exercise, error) {
+	var exercises []models.Exercise
+	if len(names) == 0 {
+		return exercises, nil
+	}
+
+	lowerNames := make([]string, 0, len(names))
+	for _, name := range names {
+		lowerNames = append(lowerNames, strings.ToLower(strings.TrimSpace(name)))
+	}
+
+	if err := r.db.Where("LOWER(name) IN ?", lowerNames).Find(&exercises).Error; err != nil {
+		return nil, err
+	}
+	return exercises, nil
+}
+
+// GetExerciseByID implements ExerciseRepository.
+func (r *exerciseRepository) GetExerciseByID(id uint) (*models.Exercise, error) {
+	var exercise models.Exercise
+	if err := r.db.First(&exercise, id).Error; err != nil {
+		return nil, err
+	}
+	return &exercise, nil
+}
+
+// GetAllExercises implements ExerciseRepository.
+func (r *exerciseRepository) GetAllExercises() ([]models.Exercise, error) {
+	var exercises []models.Exercise
+	if err := r.db.Order("name").Find(&exercises).Error; err != nil {
+		return nil, err
+	}
+	return exercises, nil
+}
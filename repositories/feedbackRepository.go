@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"strings"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FeedbackRepository is a contract of recommendation feedback repository
+type FeedbackRepository interface {
+	SaveFeedback(feedback *models.RecommendationFeedback) error
+	DeleteFeedback(userID string, target models.FeedbackTarget, name string) error
+	GetFeedbackByUser(userID string, target models.FeedbackTarget) (map[string]models.FeedbackRating, error)
+}
+
+type feedbackRepository struct {
+	db *gorm.DB
+}
+
+// NewFeedbackRepository is a constructor to create feedback repository
+func NewFeedbackRepository(db *gorm.DB) FeedbackRepository {
+	if db == nil {
+		panic("database connection cannot be nil")
+	}
+	return &feedbackRepository{
+		db: db,
+	}
+}
+
+// SaveFeedback implements FeedbackRepository.
+// An earlier rating of the same recommendation is replaced.
+func (r *feedbackRepository) SaveFeedback(feedback *models.RecommendationFeedback) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "target_type"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rating", "updated_at"}),
+	}).Create(feedback).Error
+}
+
+// DeleteFeedback implements FeedbackRepository.
+func (r *feedbackRepository) DeleteFeedback(userID string, target models.FeedbackTarget, name string) error {
+	return r.db.
+		Where("user_id = ? AND target_type = ? AND name = ?", userID, target, name).
+		Delete(&models.RecommendationFeedback{}).Error
+}
+
+// GetFeedbackByUser implements FeedbackRepository.
+// The result is keyed by the lower case name of the recommendation.
+func (r *feedbackRepository) GetFeedbackByUser(userID string, target models.FeedbackTarget) (map[string]models.FeedbackRating, error) {
+	var feedbacks []models.RecommendationFeedback
+	err := r.db.
+		Where("user_id = ? AND target_type = ?", userID, target).
+		Find(&feedbacks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]models.FeedbackRating, len(feedbacks))
+	for _, feedback := range feedbacks {
+		result[strings.ToLower(feedback.Name)] = feedback.Rating
+	}
+	return result, nil
+}
@@ -0,0 +1,167 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/cache"
+	apperrors "github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FoodCatalogRepository resolves the food labels of the ML service to foods with nutrition
+type FoodCatalogRepository interface {
+	FindFoodByLabel(label string) (*models.FoodWithNutritions, error)
+}
+
+type foodCatalogRepository struct {
+	db *gorm.DB
+}
+
+// NewFoodCatalogRepository is a constructor to create food catalog repository
+func NewFoodCatalogRepository(db *gorm.DB) FoodCatalogRepository {
+	if db == nil {
+		panic("database connection cannot be nil")
+	}
+	return &foodCatalogRepository{
+		db: db,
+	}
+}
+
+// FindFoodByLabel implements FoodCatalogRepository.
+// Labels are matched against catalog food names and aliases, so foods of users can not take over
+// a label. Unknown labels return ErrFoodNotFound.
+func (r *foodCatalogRepository) FindFoodByLabel(label string) (*models.FoodWithNutritions, error) {
+	food, err := findFoodByNameOrAlias(r.db, label, "")
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrFoodNotFound()
+		}
+		return nil, err
+	}
+	return food, nil
+}
+
+// cachedFoodCatalogRepository keeps resolved labels in memory, the catalog
+// rarely changes and is looked up for every detected item of every scan.
+type cachedFoodCatalogRepository struct {
+	next  FoodCatalogRepository
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+// NewCachedFoodCatalogRepository wraps a FoodCatalogRepository with an in-memory cache.
+func NewCachedFoodCatalogRepository(next FoodCatalogRepository, ttl time.Duration) FoodCatalogRepository {
+	if next == nil {
+		panic("next cannot be nil")
+	}
+	return &cachedFoodCatalogRepository{
+		next:  next,
+		cache: cache.NewMemoryCache(500),
+		ttl:   ttl,
+	}
+}
+
+// FindFoodByLabel implements FoodCatalogRepository.
+// Unknown labels are not cached, so foods added to the catalog are found right away.
+func (c *cachedFoodCatalogRepository) FindFoodByLabel(label string) (*models.FoodWithNutritions, error) {
+	key := "food-catalog:" + strings.ToLower(strings.TrimSpace(label))
+
+	if value, found, err := c.cache.Get(context.Background(), key); err == nil && found {
+		var food models.FoodWithNutritions
+		if err := json.Unmarshal(value, &food); err == nil {
+			return &food, nil
+		}
+	}
+
+	food, err := c.next.FindFoodByLabel(label)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(food)
+	if err != nil {
+		log.Printf("food catalog cache: failed to encode %s: %v", key, err)
+		return food, nil
+	}
+	if err := c.cache.Set(context.Background(), key, data, c.ttl); err != nil {
+		log.Printf("food catalog cache: failed to set %s: %v", key, err)
+	}
+	return food, nil
+}
+
+// findFoodByNameOrAlias finds a food with its nutrition by name, or by alias when no name matches.
+// Only foods visible to the user are found, the custom foods of the user are preferred.
+func findFoodByNameOrAlias(db *gorm.DB, name string, userID string) (*models.FoodWithNutritions, error) {
+	var foodWithNutrition models.FoodWithNutritions
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	food := db.Scopes(visibleFoods(userID), foodNamePriority(userID)).Where("LOWER(foods.name) = ?", name).First(&foodWithNutrition.Food)
+	if errors.Is(food.Error, gorm.ErrRecordNotFound) {
+		food = db.Scopes(visibleFoods(userID)).Where("foods.id = (SELECT food_id FROM food_aliases WHERE LOWER(alias) = ?)", name).First(&foodWithNutrition.Food)
+	}
+	if food.Error != nil {
+		return nil, food.Error
+	}
+
+	nutritions := db.Where("food_id = ?", foodWithNutrition.Food.ID).First(&foodWithNutrition.Nutrition)
+	if nutritions.Error != nil {
+		return nil, nutritions.Error
+	}
+
+	return &foodWithNutrition, nil
+}
+
+// findFoodsByIDs finds foods with their nutrition by ID, only foods visible to the user are found
+func findFoodsByIDs(db *gorm.DB, ids []uint, userID string) ([]models.FoodWithNutritions, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var nutritions []models.FoodNutrition
+	err := db.Preload("Food").
+		Joins("JOIN foods ON foods.id = food_nutritions.food_id").
+		Scopes(visibleFoods(userID)).
+		Where("food_nutritions.food_id IN ?", ids).
+		Order("food_nutritions.id").
+		Find(&nutritions).Error
+	if err != nil {
+		return nil, err
+	}
+	return toFoodsWithNutritions(nutritions), nil
+}
+
+// visibleFoods limits a query on foods to public foods and the custom foods of the user,
+// without a user only catalog foods are visible, not the foods users shared
+func visibleFoods(userID string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if userID == "" {
+			return db.Where("foods.owner_id IS NULL AND foods.visibility = ?", models.FoodVisibilityPublic)
+		}
+		return db.Where("(foods.visibility = ? OR foods.owner_id = ?)", models.FoodVisibilityPublic, userID)
+	}
+}
+
+// foodNamePriority orders foods with the same name, food names are not unique:
+// the custom foods of the user first, then catalog foods, then the public foods of other users,
+// the oldest food first within each. The order is one expression, gorm drops an order
+// expression merged with other order columns, like the primary key order of First.
+func foodNamePriority(userID string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		order := clause.Expr{SQL: "foods.owner_id IS NOT NULL, foods.id", WithoutParentheses: true}
+		if userID != "" {
+			order = clause.Expr{
+				SQL:                "CASE WHEN foods.owner_id = ? THEN 0 ELSE 1 END, foods.owner_id IS NOT NULL, foods.id",
+				Vars:               []interface{}{userID},
+				WithoutParentheses: true,
+			}
+		}
+		return db.Order(clause.OrderBy{Expression: order})
+	}
+}
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// newDryRunDB returns a database that builds the SQL of queries without running them
+func newDryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("open dry run database: %v", err)
+	}
+	return db
+}
+
+func TestVisibleFoods(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   string
+		wantSQL  string
+		wantVars []interface{}
+	}{
+		{
+			name:     "user sees public foods and own custom foods",
+			userID:   "user",
+			wantSQL:  `SELECT * FROM "foods" WHERE (foods.visibility = $1 OR foods.owner_id = $2)`,
+			wantVars: []interface{}{models.FoodVisibilityPublic, "user"},
+		},
+		{
+			name:     "without a user only catalog foods are visible",
+			wantSQL:  `SELECT * FROM "foods" WHERE foods.owner_id IS NULL AND foods.visibility = $1`,
+			wantVars: []interface{}{models.FoodVisibilityPublic},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt := newDryRunDB(t).Scopes(visibleFoods(tt.userID)).Find(&[]models.Food{}).Statement
+			if got := stmt.SQL.String(); got != tt.wantSQL {
+				t.Errorf("SQL = %s, want %s", got, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(stmt.Vars, tt.wantVars) {
+				t.Errorf("vars = %v, want %v", stmt.Vars, tt.wantVars)
+			}
+		})
+	}
+}
+
+func TestFoodNamePriority(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   string
+		wantSQL  string
+		wantVars []interface{}
+	}{
+		{
+			name:     "custom foods of the user first",
+			userID:   "user",
+			wantSQL:  `SELECT * FROM "foods" ORDER BY CASE WHEN foods.owner_id = $1 THEN 0 ELSE 1 END, foods.owner_id IS NOT NULL, foods.id LIMIT $2`,
+			wantVars: []interface{}{"user", 1},
+		},
+		{
+			name:     "catalog foods first without a user",
+			wantSQL:  `SELECT * FROM "foods" ORDER BY foods.owner_id IS NOT NULL, foods.id LIMIT $1`,
+			wantVars: []interface{}{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// First orders by the primary key, it must not replace the priority
+			stmt := newDryRunDB(t).Scopes(foodNamePriority(tt.userID)).First(&models.Food{}).Statement
+			if got := stmt.SQL.String(); got != tt.wantSQL {
+				t.Errorf("SQL = %s, want %s", got, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(stmt.Vars, tt.wantVars) {
+				t.Errorf("vars = %v, want %v", stmt.Vars, tt.wantVars)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FoodLogRepository is a contract of food log repository
+type FoodLogRepository interface {
+	GetFoodLogStats(userID string, since time.Time, limit int) ([]models.FoodLogStat, error)
+	GetLastPortions(userID string, foodIDs []uint) (map[uint]models.UserFoodHistory, error)
+	GetFoodsByIDs(ids []uint, userID string) ([]models.FoodWithNutritions, error)
+	GetHistoriesBetween(userID string, start, end time.Time, meal *models.MealType) ([]models.UserFoodHistory, error)
+	CreateHistories(histories []models.UserFoodHistory) error
+
+	GetFavorites(userID string) ([]models.FavoriteFood, error)
+	AddFavorite(userID string, foodID uint) error
+	RemoveFavorite(userID string, foodID uint) error
+}
+
+type foodLogRepository struct {
+	db *gorm.DB
+}
+
+// NewFoodLogRepository is a constructor to create food log repository
+func NewFoodLogRepository(db *gorm.DB) FoodLogRepository {
+	if db == nil {
+		panic("database connection cannot be nil")
+	}
+	return &foodLogRepository{
+		db: db,
+	}
+}
+
+// GetFoodLogStats implements FoodLogRepository.
+// Foods are ordered by how often they were logged since the given time, the most recent first on a tie.
+func (r *foodLogRepository) GetFoodLogStats(userID string, since time.Time, limit int) ([]models.FoodLogStat, error) {
+	var stats []models.FoodLogStat
+	err := r.db.Model(&models.UserFoodHistory{}).
+		Select("food_id, COUNT(*) AS count, MAX(created_at) AS last_logged_at").
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Group("food_id").
+		Order("count DESC, last_logged_at DESC").
+		Limit(limit).
+		Scan(&stats).Error
+	return stats, err
+}
+
+// GetLastPortions implements FoodLogRepository.
+// The result is keyed by food ID, foods never logged by the user are left out.
+func (r *foodLogRepository) GetLastPortions(userID string, foodIDs []uint) (map[uint]models.UserFoodHistory, error) {
+	portions := make(map[uint]models.UserFoodHistory)
+	if len(foodIDs) == 0 {
+		return portions, nil
+	}
+
+	var histories []models.UserFoodHistory
+	err := r.db.Raw(`
+		SELECT DISTINCT ON (food_id) *
+		FROM user_food_histories
+		WHERE user_id = ? AND food_id IN ?
+		ORDER BY food_id, created_at DESC, id DESC`, userID, foodIDs).
+		Scan(&histories).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, history := range histories {
+		portions[history.FoodID] = history
+	}
+	return portions, nil
+}
+
+// GetFoodsByIDs implements FoodLogRepository.
+// Only foods visible to the user are returned.
+func (r *foodLogRepository) GetFoodsByIDs(ids []uint, userID string) ([]models.FoodWithNutritions, error) {
+	return findFoodsByIDs(r.db, ids, userID)
+}
+
+// GetHistoriesBetween implements FoodLogRepository.
+// Only the entries of the user are returned, at one meal when a meal is given.
+func (r *foodLogRepository) GetHistoriesBetween(userID string, start, end time.Time, meal *models.MealType) ([]models.UserFoodHistory, error) {
+	query := r.db.Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, start, end)
+	if meal != nil {
+		query = query.Where("meal = ?", *meal)
+	}
+
+	var histories []models.UserFoodHistory
+	err := query.Order("created_at, id").Find(&histories).Error
+	return histories, err
+}
+
+// CreateHistories implements FoodLogRepository.
+// All entries are created in one transaction, either all or none are saved.
+func (r *foodLogRepository) CreateHistories(histories []models.UserFoodHistory) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.Omit(clause.Associations).Create(&histories).Error
+	})
+}
+
+// GetFavorites implements FoodLogRepository.
+func (r *foodLogRepository) GetFavorites(userID string) ([]models.FavoriteFood, error) {
+	var favorites []models.FavoriteFood
+	err := r.db.Where("user_id = ?", userID).Order("created_at").Find(&favorites).Error
+	return favorites, err
+}
+
+// AddFavorite implements FoodLogRepository.
+// Pinning a food twice keeps the first pin.
+func (r *foodLogRepository) AddFavorite(userID string, foodID uint) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.FavoriteFood{
+		UserID: userID,
+		FoodID: foodID,
+	}).Error
+}
+
+// RemoveFavorite implements FoodLogRepository.
+func (r *foodLogRepository) RemoveFavorite(userID string, foodID uint) error {
+	return r.db.Where("user_id = ? AND food_id = ?", userID, foodID).Delete(&models.FavoriteFood{}).Error
+}
@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"strings"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"gorm.io/gorm"
+)
+
+// FoodTagRepository is a contract of food tag repository
+type FoodTagRepository interface {
+	GetTagsByFoodNames(names []string) (map[string][]string, error)
+}
+
+type foodTagRepository struct {
+	db *gorm.DB
+}
+
+// NewFoodTagRepository is a constructor to create food tag repository
+func NewFoodTagRepository(db *gorm.DB) FoodTagRepository {
+	if db == nil {
+		panic("database connection cannot be nil")
+	}
+	return &foodTagRepository{
+		db: db,
+	}
+}
+
+// GetTagsByFoodNames implements FoodTagRepository.
+// Catalog foods, their aliases and name tags are matched, tags of custom foods can not vouch for a catalog food.
+// The result is keyed by the lower case food name, foods without tags are left out.
+func (r *foodTagRepository) GetTagsByFoodNames(names []string) (map[string][]string, error) {
+	result := make(map[string][]string)
+	if len(names) == 0 {
+		return result, nil
+	}
+
+	lowered := make([]string, len(names))
+	for i, name := range names {
+		lowered[i] = strings.ToLower(name)
+	}
+
+	var foods []models.Food
+	err := r.db.Preload("Tags").
+		Where("owner_id IS NULL AND LOWER(name) IN ?", lowered).
+		Find(&foods).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, food := range foods {
+		key := strings.ToLower(food.Name)
+		for _, tag := range food.Tags {
+			result[key] = appendTag(result[key], tag.Name)
+		}
+	}
+
+	// foods known by another name, e.g. "Ayam" for "Chicken", get the tags of the catalog food
+	var aliases []models.FoodAlias
+	err = r.db.Preload("Food.Tags").
+		Joins("JOIN foods ON foods.id = food_aliases.food_id").
+		Where("foods.owner_id IS NULL AND LOWER(food_aliases.alias) IN ?", lowered).
+		Find(&aliases).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, alias := range aliases {
+		key := strings.ToLower(alias.Alias)
+		for _, tag := range alias.Food.Tags {
+			result[key] = appendTag(result[key], tag.Name)
+		}
+	}
+
+	// dishes of the ML service are tagged by name until they are added to the catalog
+	var nameTags []models.FoodNameTag
+	err = r.db.Preload("Tag").
+		Where("name IN ?", lowered).
+		Find(&nameTags).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, nameTag := range nameTags {
+		result[nameTag.Name] = appendTag(result[nameTag.Name], nameTag.Tag.Name)
+	}
+	return result, nil
+}
+
+// appendTag adds a tag to the list unless the list already has it
+func appendTag(tags []string, tag string) []string {
+	for _, t := range tags {
+		if t == tag {
+			return tags
+		}
+	}
+	return append(tags, tag)
+}
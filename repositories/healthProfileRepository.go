@@ -21,6 +21,9 @@ type HealthProfileRepository interface {
 	DeleteAssessment(assessment *models.RiskAssessment) error
 
 	CheckHealthProfileExist(userID string) (bool, error)
+
+	GetDietaryPreferences(userID string) ([]models.DietaryPreference, error)
+	ReplaceDietaryPreferences(userID string, preferences []models.DietaryPreference) error
 }
 type healthProfileRepository struct {
 	db *gorm.DB
@@ -153,3 +156,39 @@ func (h *healthProfileRepository) CheckHealthProfileExist(userID string) (bool,
 	}
 	return true, nil
 }
+
+// GetDietaryPreferences implements HealthProfileRepository.
+func (h *healthProfileRepository) GetDietaryPreferences(userID string) ([]models.DietaryPreference, error) {
+	var preferences []models.DietaryPreference
+	err := h.db.Model(&models.UserDietaryPreference{}).
+		Where("user_id = ?", userID).
+		Order("preference").
+		Pluck("preference", &preferences).Error
+	if err != nil {
+		return nil, err
+	}
+	return preferences, nil
+}
+
+// ReplaceDietaryPreferences implements HealthProfileRepository.
+func (h *healthProfileRepository) ReplaceDietaryPreferences(userID string, preferences []models.DietaryPreference) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.UserDietaryPreference{}).Error; err != nil {
+			return err
+		}
+		if len(preferences) == 0 {
+			return nil
+		}
+
+		rows := make([]models.UserDietaryPreference, 0, len(preferences))
+		seen := make(map[models.DietaryPreference]bool)
+		for _, preference := range preferences {
+			if seen[preference] {
+				continue
+			}
+			seen[preference] = true
+			rows = append(rows, models.UserDietaryPreference{UserID: userID, Preference: preference})
+		}
+		return tx.Create(&rows).Error
+	})
+}
@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"strings"
+	"time"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"gorm.io/gorm"
+)
+
+// MealPlanRepository is a contract of meal plan repository
+type MealPlanRepository interface {
+	GetPlanByDate(userID string, date time.Time) (*models.MealPlan, error)
+	CreatePlan(plan *models.MealPlan) error
+	GetItemByID(id uint) (*models.MealPlanItem, error)
+	GetPlanByID(id uint) (*models.MealPlan, error)
+	UpdateItem(item *models.MealPlanItem) error
+	GetFoodCandidates(limit int) ([]models.FoodWithNutritions, error)
+	GetFoodsByNames(names []string) ([]models.FoodWithNutritions, error)
+	GetFoodByID(id uint, userID string) (*models.FoodWithNutritions, error)
+}
+
+type mealPlanRepository struct {
+	db *gorm.DB
+}
+
+// NewMealPlanRepository is a constructor to create meal plan repository
+func NewMealPlanRepository(db *gorm.DB) MealPlanRepository {
+	if db == nil {
+		panic("database connection cannot be nil")
+	}
+	return &mealPlanRepository{
+		db: db,
+	}
+}
+
+// GetPlanByDate implements MealPlanRepository.
+func (r *mealPlanRepository) GetPlanByDate(userID string, date time.Time) (*models.MealPlan, error) {
+	var plan models.MealPlan
+	err := r.db.
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Where("user_id = ? AND date = ?", userID, date.Format("2006-01-02")).
+		First(&plan).Error
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// CreatePlan implements MealPlanRepository.
+func (r *mealPlanRepository) CreatePlan(plan *models.MealPlan) error {
+	return r.db.Create(plan).Error
+}
+
+// GetItemByID implements MealPlanRepository.
+func (r *mealPlanRepository) GetItemByID(id uint) (*models.MealPlanItem, error) {
+	var item models.MealPlanItem
+	if err := r.db.First(&item, id).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// GetPlanByID implements MealPlanRepository.
+func (r *mealPlanRepository) GetPlanByID(id uint) (*models.MealPlan, error) {
+	var plan models.MealPlan
+	err := r.db.
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		First(&plan, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// UpdateItem implements MealPlanRepository.
+func (r *mealPlanRepository) UpdateItem(item *models.MealPlanItem) error {
+	return r.db.Save(item).Error
+}
+
+// GetFoodCandidates implements MealPlanRepository.
+// Only catalog foods with a known calorie value can be used in a meal plan.
+func (r *mealPlanRepository) GetFoodCandidates(limit int) ([]models.FoodWithNutritions, error) {
+	var nutritions []models.FoodNutrition
+	err := r.db.Preload("Food").
+		Joins("JOIN foods ON foods.id = food_nutritions.food_id").
+		Scopes(visibleFoods("")).
+		Where("food_nutritions.calories > 0 AND food_nutritions.weight > 0").
+		Order("food_nutritions.food_id").
+		Limit(limit).
+		Find(&nutritions).Error
+	if err != nil {
+		return nil, err
+	}
+	return toFoodsWithNutritions(nutritions), nil
+}
+
+// GetFoodsByNames implements MealPlanRepository.
+// Only catalog foods are found, the oldest food first when names repeat.
+func (r *mealPlanRepository) GetFoodsByNames(names []string) ([]models.FoodWithNutritions, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	lowered := make([]string, len(names))
+	for i, name := range names {
+		lowered[i] = strings.ToLower(name)
+	}
+
+	var nutritions []models.FoodNutrition
+	err := r.db.Preload("Food").
+		Joins("JOIN foods ON foods.id = food_nutritions.food_id").
+		Scopes(visibleFoods("")).
+		Where("LOWER(foods.name) IN ?", lowered).
+		Where("food_nutritions.calories > 0 AND food_nutritions.weight > 0").
+		Order("foods.id").
+		Find(&nutritions).Error
+	if err != nil {
+		return nil, err
+	}
+	return toFoodsWithNutritions(nutritions), nil
+}
+
+// GetFoodByID implements MealPlanRepository.
+// Custom foods of other users are not found unless they are public.
+func (r *mealPlanRepository) GetFoodByID(id uint, userID string) (*models.FoodWithNutritions, error) {
+	var nutrition models.FoodNutrition
+	err := r.db.Preload("Food").
+		Joins("JOIN foods ON foods.id = food_nutritions.food_id").
+		Scopes(visibleFoods(userID)).
+		Where("food_nutritions.food_id = ?", id).
+		First(&nutrition).Error
+	if err != nil {
+		return nil, err
+	}
+	return &models.FoodWithNutritions{Food: nutrition.Food, Nutrition: nutrition}, nil
+}
+
+func toFoodsWithNutritions(nutritions []models.FoodNutrition) []models.FoodWithNutritions {
+	foods := make([]models.FoodWithNutritions, 0, len(nutritions))
+	for _, nutrition := range nutritions {
+		foods = append(foods, models.FoodWithNutritions{Food: nutrition.Food, Nutrition: nutrition})
+	}
+	return foods
+}
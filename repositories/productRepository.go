@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	apperrors "github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProductRepository looks up packaged foods by barcode
+type ProductRepository interface {
+	FindProductByBarcode(barcode string) (*models.Product, error)
+	GetProductFood(product *models.Product) (*models.FoodWithNutritions, error)
+}
+
+type productRepository struct {
+	db *gorm.DB
+}
+
+// NewProductRepository is a constructor to create product repository
+func NewProductRepository(db *gorm.DB) ProductRepository {
+	if db == nil {
+		panic("database connection cannot be nil")
+	}
+	return &productRepository{
+		db: db,
+	}
+}
+
+// FindProductByBarcode implements ProductRepository.
+// The barcode must be normalized, unknown barcodes return ErrProductNotFound.
+func (r *productRepository) FindProductByBarcode(barcode string) (*models.Product, error) {
+	var product models.Product
+	if err := r.db.Where("barcode = ?", barcode).First(&product).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrProductNotFound()
+		}
+		return nil, err
+	}
+	return &product, nil
+}
+
+// GetProductFood implements ProductRepository.
+// The food of a product is created on the first lookup, so the product can be logged
+// and found like any other food afterwards. Its nutrition is refreshed when the product
+// was re-imported with other values.
+func (r *productRepository) GetProductFood(product *models.Product) (*models.FoodWithNutritions, error) {
+	var result models.FoodWithNutritions
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// lock the product so concurrent lookups create only one food
+		var locked models.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, product.ID).Error; err != nil {
+			return err
+		}
+
+		if locked.FoodID != nil {
+			var nutrition models.FoodNutrition
+			err := tx.Preload("Food").Where("food_id = ?", *locked.FoodID).First(&nutrition).Error
+			if err == nil {
+				// a re-import may have corrected the product, the food follows it
+				if !sameProductNutrition(&locked, &nutrition) {
+					nutrition.Calories = locked.Calories
+					nutrition.Sugar = locked.Sugar
+					nutrition.Fat = locked.Fat
+					nutrition.Carbohydrates = locked.Carbohydrates
+					nutrition.Proteins = locked.Proteins
+					nutrition.Weight = 100
+					if err := tx.Omit(clause.Associations).Save(&nutrition).Error; err != nil {
+						return err
+					}
+				}
+				result = models.FoodWithNutritions{Food: nutrition.Food, Nutrition: nutrition}
+				return nil
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+		}
+
+		food := models.Food{Name: productFoodName(&locked)}
+		if err := tx.Create(&food).Error; err != nil {
+			return err
+		}
+		nutrition := models.FoodNutrition{
+			FoodID:        food.ID,
+			Calories:      locked.Calories,
+			Sugar:         locked.Sugar,
+			Fat:           locked.Fat,
+			Carbohydrates: locked.Carbohydrates,
+			Proteins:      locked.Proteins,
+			Weight:        100,
+		}
+		if err := tx.Create(&nutrition).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&locked).Update("food_id", food.ID).Error; err != nil {
+			return err
+		}
+
+		product.FoodID = &food.ID
+		result = models.FoodWithNutritions{Food: food, Nutrition: nutrition}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// sameProductNutrition reports whether the nutrition of a food matches its product, per 100 gram
+func sameProductNutrition(product *models.Product, nutrition *models.FoodNutrition) bool {
+	return nutrition.Weight == 100 &&
+		nutrition.Calories == product.Calories &&
+		nutrition.Sugar == product.Sugar &&
+		nutrition.Fat == product.Fat &&
+		nutrition.Carbohydrates == product.Carbohydrates &&
+		nutrition.Proteins == product.Proteins
+}
+
+// productFoodName names the food of a product after the product and its brand
+func productFoodName(product *models.Product) string {
+	name := strings.TrimSpace(product.Name)
+	brand := strings.TrimSpace(product.Brand)
+	if brand == "" || strings.Contains(strings.ToLower(name), strings.ToLower(brand)) {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, brand)
+}
@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RecipeRepository is a contract of recipe repository
+type RecipeRepository interface {
+	CreateRecipe(recipe *models.Recipe, nutrition *models.FoodNutrition) error
+	UpdateRecipe(recipe *models.Recipe, nutrition *models.FoodNutrition) error
+	GetRecipeByID(id uint) (*models.Recipe, error)
+	GetRecipesByOwner(userID string) ([]models.Recipe, error)
+	GetRecipesByIngredient(foodID uint) ([]models.Recipe, error)
+	GetFoodsByIDs(ids []uint, userID string) ([]models.FoodWithNutritions, error)
+	GetFoodNutrition(foodID uint) (*models.FoodNutrition, error)
+}
+
+type recipeRepository struct {
+	db *gorm.DB
+}
+
+// NewRecipeRepository is a constructor to create recipe repository
+func NewRecipeRepository(db *gorm.DB) RecipeRepository {
+	if db == nil {
+		panic("database connection cannot be nil")
+	}
+	return &recipeRepository{
+		db: db,
+	}
+}
+
+// CreateRecipe implements RecipeRepository.
+// The food of the recipe is created with the nutrition of one serving.
+func (r *recipeRepository) CreateRecipe(recipe *models.Recipe, nutrition *models.FoodNutrition) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(&recipe.Food).Error; err != nil {
+			return err
+		}
+		nutrition.FoodID = recipe.Food.ID
+		if err := tx.Omit(clause.Associations).Create(nutrition).Error; err != nil {
+			return err
+		}
+
+		recipe.FoodID = recipe.Food.ID
+		if err := tx.Omit(clause.Associations).Create(recipe).Error; err != nil {
+			return err
+		}
+		return createIngredients(tx, recipe)
+	})
+}
+
+// UpdateRecipe implements RecipeRepository.
+// The ingredients are replaced by the ingredients of the recipe.
+func (r *recipeRepository) UpdateRecipe(recipe *models.Recipe, nutrition *models.FoodNutrition) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(&recipe.Food).Error; err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).Save(nutrition).Error; err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).Save(recipe).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("recipe_id = ?", recipe.ID).Delete(&models.RecipeIngredient{}).Error; err != nil {
+			return err
+		}
+		return createIngredients(tx, recipe)
+	})
+}
+
+func createIngredients(tx *gorm.DB, recipe *models.Recipe) error {
+	for i := range recipe.Ingredients {
+		recipe.Ingredients[i].ID = 0
+		recipe.Ingredients[i].RecipeID = recipe.ID
+	}
+	return tx.Omit(clause.Associations).Create(&recipe.Ingredients).Error
+}
+
+// GetRecipeByID implements RecipeRepository.
+func (r *recipeRepository) GetRecipeByID(id uint) (*models.Recipe, error) {
+	var recipe models.Recipe
+	err := r.db.Preload("Food").
+		Preload("Ingredients", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		First(&recipe, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &recipe, nil
+}
+
+// GetRecipesByOwner implements RecipeRepository.
+func (r *recipeRepository) GetRecipesByOwner(userID string) ([]models.Recipe, error) {
+	var recipes []models.Recipe
+	err := r.db.Preload("Food").
+		Preload("Ingredients", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Where("owner_id = ?", userID).
+		Order("created_at DESC").
+		Find(&recipes).Error
+	return recipes, err
+}
+
+// GetRecipesByIngredient implements RecipeRepository.
+// The recipes of every user having the food as an ingredient are returned.
+func (r *recipeRepository) GetRecipesByIngredient(foodID uint) ([]models.Recipe, error) {
+	var recipes []models.Recipe
+	err := r.db.Preload("Food").
+		Preload("Ingredients", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Where("id IN (?)", r.db.Model(&models.RecipeIngredient{}).Select("recipe_id").Where("food_id = ?", foodID)).
+		Order("id").
+		Find(&recipes).Error
+	return recipes, err
+}
+
+// GetFoodsByIDs implements RecipeRepository.
+// Only foods visible to the user are returned.
+func (r *recipeRepository) GetFoodsByIDs(ids []uint, userID string) ([]models.FoodWithNutritions, error) {
+	return findFoodsByIDs(r.db, ids, userID)
+}
+
+// GetFoodNutrition implements RecipeRepository.
+func (r *recipeRepository) GetFoodNutrition(foodID uint) (*models.FoodNutrition, error) {
+	var nutrition models.FoodNutrition
+	if err := r.db.Where("food_id = ?", foodID).First(&nutrition).Error; err != nil {
+		return nil, err
+	}
+	return &nutrition, nil
+}
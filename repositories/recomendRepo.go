@@ -1,127 +1,66 @@
 package repositories
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
+	"context"
 
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/mlclient"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/resilience"
 )
 
 type RecomendationRepo interface {
 	GetFoodRecomendations(diabetPercentage float32) (*dto.FoodRecomendationClientResp, error)
-	//TODO: Get Exercice recomendation
 	GetExerciceRecomendations(data *dto.ExerciseRequest) (*dto.ExerciseRecommendationClientResp, error)
 	DiabetesPrediction(data *dto.DiabetesPredictionRequest) (*dto.DiabetesPredictionClientResp, error)
 }
 
 type recomendationRepo struct {
-	httpClient *http.Client
+	mlClient   *mlclient.Client
+	mlUpstream *resilience.Upstream
 }
 
-func NewRecomendationRepo(httpClient *http.Client) RecomendationRepo {
-	if httpClient == nil {
-		panic("httpClient cannot be nil")
+func NewRecomendationRepo(mlClient *mlclient.Client, mlUpstream *resilience.Upstream) RecomendationRepo {
+	if mlClient == nil {
+		panic("mlClient cannot be nil")
+	}
+	if mlUpstream == nil {
+		panic("mlUpstream cannot be nil")
 	}
 	return &recomendationRepo{
-		httpClient: httpClient,
+		mlClient:   mlClient,
+		mlUpstream: mlUpstream,
 	}
 }
 
+// GetFoodRecomendations implements RecomendationRepo.
 func (r *recomendationRepo) GetFoodRecomendations(diabetPercentage float32) (*dto.FoodRecomendationClientResp, error) {
-	data := map[string]interface{}{
-		"diabetes_percentage": diabetPercentage,
-	}
-
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		fmt.Println("Error encoding JSON:", err)
-		return nil, err
-	}
-
-	fmt.Print("Request Body: ")
-
-	resp, err := r.httpClient.Post("https://ml.sweetlife.my.id//food_recommendation", "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, err
-	}
-
-	fmt.Println("Response Status:", resp.Status)
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Println("Error reading response body:", err)
-		return nil, err
-	}
-
-	// Decode JSON ke dalam struktur Go
-	var recomendation dto.FoodRecomendationClientResp
-	err = json.Unmarshal(body, &recomendation)
-	if err != nil {
-		fmt.Println("Error decoding JSON:", err)
-		return nil, err
-	}
-
-	fmt.Println("Recomendation:", recomendation)
-
-	return &recomendation, nil
+	var resp *dto.FoodRecomendationClientResp
+	err := r.mlUpstream.Execute(context.Background(), func(ctx context.Context) error {
+		var err error
+		resp, err = r.mlClient.FoodRecommendation(ctx, diabetPercentage)
+		return err
+	})
+	return resp, err
 }
 
+// DiabetesPrediction implements RecomendationRepo.
 func (r *recomendationRepo) DiabetesPrediction(data *dto.DiabetesPredictionRequest) (*dto.DiabetesPredictionClientResp, error) {
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		fmt.Println("Error encoding JSON:", err)
-		return nil, err
-	}
-
-	resp, err := r.httpClient.Post("https://ml.sweetlife.my.id/diabetes_predict", "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Println("Error reading response body:", err)
-		return nil, err
-	}
-
-	var prediction dto.DiabetesPredictionClientResp
-	err = json.Unmarshal(body, &prediction)
-	if err != nil {
-		fmt.Println("Error decoding JSON:", err)
-		return nil, err
-	}
-
-	return &prediction, nil
+	var resp *dto.DiabetesPredictionClientResp
+	err := r.mlUpstream.Execute(context.Background(), func(ctx context.Context) error {
+		var err error
+		resp, err = r.mlClient.DiabetesPrediction(ctx, data)
+		return err
+	})
+	return resp, err
 }
 
 // GetExerciceRecomendations implements RecomendationRepo.
 func (r *recomendationRepo) GetExerciceRecomendations(data *dto.ExerciseRequest) (*dto.ExerciseRecommendationClientResp, error) {
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return nil, err
-	}
-
-	fmt.Print("Request Body: " + string(jsonData))
-
-	resp, err := r.httpClient.Post("https://ml.sweetlife.my.id/exercise_recommendation", "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, err
-	}
-
-	fmt.Println("Response Status:", resp.Status)
-
-	// Decode JSON ke dalam struktur Go
-	var recomendation dto.ExerciseRecommendationClientResp
-	err = json.NewDecoder(resp.Body).Decode(&recomendation)
-	if err != nil {
-		fmt.Println("Error decoding JSON:", err)
-		return nil, err
-	}
-
-	fmt.Println("Recomendation:", recomendation)
-
-	return &recomendation, nil
+	var resp *dto.ExerciseRecommendationClientResp
+	err := r.mlUpstream.Execute(context.Background(), func(ctx context.Context) error {
+		var err error
+		resp, err = r.mlClient.ExerciseRecommendation(ctx, data)
+		return err
+	})
+	return resp, err
 }
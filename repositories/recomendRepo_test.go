@@ -0,0 +1,201 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/cache"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/mlclient"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/mlstub"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/resilience"
+)
+
+// newMLTestClient starts the ML stub and returns a client and circuit breaker for it
+func newMLTestClient(t *testing.T) (*mlclient.Client, *resilience.Upstream) {
+	t.Helper()
+	server := mlstub.NewServer()
+	t.Cleanup(server.Close)
+
+	upstream := resilience.Register(testUpstreamName(t), resilience.Settings{
+		MaxRetries:  1,
+		BaseBackoff: time.Millisecond,
+		MaxBackoff:  time.Millisecond,
+		Retryable:   mlclient.IsRetryable,
+	})
+	return mlclient.NewClient(server.URL, &http.Client{Timeout: 5 * time.Second}), upstream
+}
+
+func TestRecomendationRepoFoodRecomendations(t *testing.T) {
+	repo := NewRecomendationRepo(newMLTestClient(t))
+
+	resp, err := repo.GetFoodRecomendations(72)
+	if err != nil {
+		t.Fatalf("GetFoodRecomendations() error = %v", err)
+	}
+	if !resp.Diabetes {
+		t.Error("Diabetes = false, want true for a 72% risk")
+	}
+	if len(resp.FoodRecomendation) == 0 || len(resp.FoodRecomendation[0]) == 0 {
+		t.Fatal("no food recommendations")
+	}
+	for _, group := range resp.FoodRecomendation {
+		for _, food := range group {
+			if food.Name == "" || food.Calories <= 0 {
+				t.Errorf("recommendation %+v has no name or calories", food)
+			}
+		}
+	}
+}
+
+func TestRecomendationRepoDiabetesPrediction(t *testing.T) {
+	repo := NewRecomendationRepo(newMLTestClient(t))
+
+	low, err := repo.DiabetesPrediction(&dto.DiabetesPredictionRequest{Age: 25, BMI: 21})
+	if err != nil {
+		t.Fatalf("DiabetesPrediction() error = %v", err)
+	}
+	high, err := repo.DiabetesPrediction(&dto.DiabetesPredictionRequest{Age: 60, BMI: 32, HeartDisease: true})
+	if err != nil {
+		t.Fatalf("DiabetesPrediction() error = %v", err)
+	}
+	if high.Percentage <= low.Percentage {
+		t.Errorf("high risk percentage %v is not above low risk percentage %v", high.Percentage, low.Percentage)
+	}
+}
+
+func TestRecomendationRepoExerciseRecomendations(t *testing.T) {
+	repo := NewRecomendationRepo(newMLTestClient(t))
+
+	resp, err := repo.GetExerciceRecomendations(&dto.ExerciseRequest{Gender: "Male", Age: 40, Height: 170, Bmi: 27, Diabetes: true})
+	if err != nil {
+		t.Fatalf("GetExerciceRecomendations() error = %v", err)
+	}
+	if len(resp.ExerciseCategories) == 0 || resp.ExerciseDuration <= 0 {
+		t.Errorf("GetExerciceRecomendations() = %+v, want exercises with a duration", resp)
+	}
+}
+
+func TestRecomendationRepoUnavailable(t *testing.T) {
+	_, upstream := newMLTestClient(t)
+	// nothing listens on a closed stub server
+	server := mlstub.NewServer()
+	server.Close()
+	repo := NewRecomendationRepo(mlclient.NewClient(server.URL, nil), upstream)
+
+	_, err := repo.GetFoodRecomendations(30)
+	if !errors.Is(err, mlclient.ErrRequestFailed) {
+		t.Errorf("GetFoodRecomendations() error = %v, want %v", err, mlclient.ErrRequestFailed)
+	}
+}
+
+func TestCachedRecomendationRepoUsesCache(t *testing.T) {
+	server := mlstub.NewServer()
+	defer server.Close()
+
+	calls := 0
+	counting := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+	upstream := resilience.Register(testUpstreamName(t), resilience.Settings{Retryable: mlclient.IsRetryable})
+	repo := NewCachedRecomendationRepo(
+		NewRecomendationRepo(mlclient.NewClient(server.URL, counting), upstream),
+		cache.NewMemoryCache(10), time.Minute, "test",
+	)
+
+	// risks in the same bucket share the cached answer
+	for _, risk := range []float32{41.8, 42.2, 42} {
+		if _, err := repo.GetFoodRecomendations(risk); err != nil {
+			t.Fatalf("GetFoodRecomendations(%v) error = %v", risk, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("ML service calls = %d, want 1", calls)
+	}
+
+	if _, err := repo.GetFoodRecomendations(80); err != nil {
+		t.Fatalf("GetFoodRecomendations() error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("ML service calls = %d, want 2 after a new risk bucket", calls)
+	}
+}
+
+func TestScanFoodRepoScanFood(t *testing.T) {
+	client, upstream := newMLTestClient(t)
+	repo := NewScanFoodRepository(nil, client, upstream, nil, nil, "")
+
+	resp, err := repo.ScanFood("https://example.com/food.jpg")
+	if err != nil {
+		t.Fatalf("ScanFood() error = %v", err)
+	}
+	if len(resp.Objects) == 0 {
+		t.Fatal("ScanFood() found no foods")
+	}
+	for _, object := range resp.Objects {
+		if object.Name == "" || object.Unit <= 0 {
+			t.Errorf("scanned food %+v has no name or unit", object)
+		}
+	}
+}
+
+func TestScanFoodRepoOnlyRetriesConnectionErrors(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	closed := mlstub.NewServer()
+	closed.Close()
+
+	tests := []struct {
+		name      string
+		url       string
+		wantErr   error
+		wantCalls int
+	}{
+		// the ML service may have started scanning, the scan is not sent again
+		{"server error", failing.URL, mlclient.ErrUnexpectedStatus, 1},
+		{"connection refused", closed.URL, mlclient.ErrRequestFailed, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			counting := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				calls++
+				return http.DefaultTransport.RoundTrip(req)
+			})}
+			upstream := resilience.Register(testUpstreamName(t), resilience.Settings{
+				MaxRetries:  2,
+				BaseBackoff: time.Millisecond,
+				MaxBackoff:  time.Millisecond,
+				Retryable:   mlclient.IsRetryable,
+			})
+			repo := NewScanFoodRepository(nil, mlclient.NewClient(tt.url, counting), upstream, nil, nil, "")
+
+			_, err := repo.ScanFood("https://example.com/food.jpg")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ScanFood() error = %v, want %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("ML service calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+// testUpstreamName is unique per test run, so repeated runs do not share a circuit breaker
+func testUpstreamName(t *testing.T) string {
+	return fmt.Sprintf("%s-%d", t.Name(), time.Now().UnixNano())
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -1,180 +1,455 @@
 package repositories
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+	apperrors "github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/mlclient"
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/resilience"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ScanFoodRepository interface {
 	ScanFood(image string) (*dto.ScanFoodClientResp, error)
-	SearchFoodFromDB(name string) (*models.FoodWithNutritions, error)
-	SearchFoodAPI(foodName string) (*dto.FoodNutritionResponse, error)
+	SearchFoodFromDB(name string, userID string) (*models.FoodWithNutritions, error)
+	SearchFoods(query string, limit int, userID string) ([]models.FoodSearchResult, error)
+	SearchFoodAPI(query string, limit int) ([]dto.FoodNutritionResponse, error)
+	GetFoodAPI(fdcID int) (*dto.FoodNutritionResponse, error)
 
-	GetFoodIDs(foodNames *[]dto.ScanFood) (map[string]uint, error)
+	GetFoodIDs(foodNames *[]dto.ScanFood, userID string) (map[string]uint, error)
 
-	CreateFood(food *models.Food) error
-	CreateFoodNutrition(foodNutrition *models.FoodNutrition) error
+	GetFoodByFdcID(fdcID int) (*models.FoodWithNutritions, error)
+	CreateFoodWithNutrition(food *models.Food, nutrition *models.FoodNutrition) error
 
 	SaveUserFoodHistory(food *[]models.UserFoodHistory) error
+	RecordUnmatchedLabel(label string) error
+
+	CreateScanSession(session *models.ScanSession) error
+	GetScanSessionByID(id uint) (*models.ScanSession, error)
+	UpdateScanSession(session *models.ScanSession) error
+	FailStaleScanSessions(before time.Time, message string) (int64, error)
+}
+
+const (
+	usdaBaseURL = "https://api.nal.usda.gov/fdc/v1"
+	// kilojoulesPerKilocalorie converts energy reported in kJ only
+	kilojoulesPerKilocalorie = 4.184
+)
+
+// USDAStatusError is returned when the USDA API answers with a non 2xx status code.
+type USDAStatusError struct {
+	StatusCode int
+}
+
+// Error implements error.
+func (e *USDAStatusError) Error() string {
+	return fmt.Sprintf("usda api returned status %d", e.StatusCode)
+}
+
+// IsRetryableUSDAError reports whether a USDA API error is transient.
+func IsRetryableUSDAError(err error) bool {
+	var statusErr *USDAStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+	// network errors and timeouts
+	return true
 }
 
 type scanFoodRepository struct {
-	httpClient *http.Client
-	db         *gorm.DB
-	apiKey     string
+	httpClient   *http.Client
+	mlClient     *mlclient.Client
+	mlUpstream   *resilience.Upstream
+	usdaUpstream *resilience.Upstream
+	db           *gorm.DB
+	apiKey       string
 }
 
-// CreateFood implements ScanFoodRepository.
-func NewScanFoodRepository(httpClient *http.Client, db *gorm.DB, apiKey string) ScanFoodRepository {
+// NewScanFoodRepository creates a new instance of ScanFoodRepository.
+func NewScanFoodRepository(httpClient *http.Client, mlClient *mlclient.Client, mlUpstream, usdaUpstream *resilience.Upstream, db *gorm.DB, apiKey string) ScanFoodRepository {
 	return &scanFoodRepository{
-		httpClient: httpClient,
-		db:         db,
-		apiKey:     apiKey,
+		httpClient:   httpClient,
+		mlClient:     mlClient,
+		mlUpstream:   mlUpstream,
+		usdaUpstream: usdaUpstream,
+		db:           db,
+		apiKey:       apiKey,
 	}
 }
 
+// ScanFood implements ScanFoodRepository.
+// Scanning is not idempotent, it is only retried when the ML service could not be reached.
 func (s *scanFoodRepository) ScanFood(image string) (*dto.ScanFoodClientResp, error) {
-	data := map[string]interface{}{
-		"image": image,
+	var resp *dto.ScanFoodClientResp
+	err := s.mlUpstream.ExecuteWith(context.Background(), mlclient.IsConnectionError, func(ctx context.Context) error {
+		var err error
+		resp, err = s.mlClient.ScanFood(ctx, image)
+		return err
+	})
+	return resp, err
+}
+
+// SearchFood implements ScanFoodRepository.
+// The name is matched against food names first and then against food aliases.
+// Custom foods of the user are preferred, custom foods of other users are only found when public.
+func (s *scanFoodRepository) SearchFoodFromDB(name string, userID string) (*models.FoodWithNutritions, error) {
+	return findFoodByNameOrAlias(s.db, name, userID)
+}
+
+// foodMatchSQL scores how well a name column matches the query,
+// by trigram similarity, full-text rank and prefix
+const foodMatchSQL = `
+	SELECT %[1]s AS food_id, GREATEST(
+		similarity(LOWER(%[2]s), @query),
+		ts_rank(to_tsvector('simple', %[2]s), plainto_tsquery('simple', @query)),
+		CASE WHEN LOWER(%[2]s) LIKE @prefix THEN 1 ELSE 0 END
+	) AS score
+	FROM %[3]s
+	WHERE LOWER(%[2]s) %% @query
+		OR to_tsvector('simple', %[2]s) @@ plainto_tsquery('simple', @query)
+		OR LOWER(%[2]s) LIKE @contains`
+
+// SearchFoods implements ScanFoodRepository.
+// Food names and aliases are matched by trigram similarity, full-text search and prefix,
+// so typos and partial names typed during autocomplete still find the food. Prefix matches rank first,
+// among equally good matches the custom foods of the user come first, then catalog foods.
+func (s *scanFoodRepository) SearchFoods(query string, limit int, userID string) ([]models.FoodSearchResult, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(query)
+
+	// only public foods and the custom foods of the user can be found, without a user only catalog foods
+	visible := "owner_id IS NULL AND visibility = @public"
+	own := "0"
+	if userID != "" {
+		visible = "(visibility = @public OR owner_id = @user)"
+		own = "CASE WHEN owner_id = @user THEN 1 ELSE 0 END"
 	}
 
-	jsonData, err := json.Marshal(data)
+	var rows []struct {
+		FoodID uint
+		Score  float64
+	}
+	err := s.db.Raw(
+		"SELECT food_id, MAX(score) AS score FROM ("+
+			fmt.Sprintf(foodMatchSQL, "foods.id", "foods.name", "foods")+
+			" UNION ALL "+
+			fmt.Sprintf(foodMatchSQL, "food_aliases.food_id", "food_aliases.alias", "food_aliases")+
+			") matches JOIN foods ON foods.id = matches.food_id WHERE "+visible+
+			" GROUP BY food_id, owner_id ORDER BY score DESC, "+own+" DESC, owner_id IS NULL DESC, food_id LIMIT @limit",
+		map[string]interface{}{
+			"query":    query,
+			"prefix":   escaped + "%",
+			"contains": "%" + escaped + "%",
+			"limit":    limit,
+			"public":   models.FoodVisibilityPublic,
+			"user":     userID,
+		}).
+		Scan(&rows).Error
 	if err != nil {
 		return nil, err
 	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
 
-	resp, err := s.httpClient.Post("https://ml.sweetlife.my.id/scan-food", "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, err
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.FoodID
 	}
 
-	var scanFoodResponse dto.ScanFoodClientResp
-	err = json.NewDecoder(resp.Body).Decode(&scanFoodResponse)
-	if err != nil {
+	var nutritions []models.FoodNutrition
+	if err := s.db.Preload("Food").Where("food_id IN ?", ids).Find(&nutritions).Error; err != nil {
 		return nil, err
 	}
+	nutritionByFood := make(map[uint]models.FoodNutrition, len(nutritions))
+	for _, nutrition := range nutritions {
+		if _, found := nutritionByFood[nutrition.FoodID]; !found {
+			nutritionByFood[nutrition.FoodID] = nutrition
+		}
+	}
 
-	return &scanFoodResponse, nil
+	// keep the ranking, foods without nutrition can not be logged and are left out
+	results := make([]models.FoodSearchResult, 0, len(rows))
+	for _, row := range rows {
+		nutrition, found := nutritionByFood[row.FoodID]
+		if !found {
+			continue
+		}
+		results = append(results, models.FoodSearchResult{
+			FoodWithNutritions: models.FoodWithNutritions{Food: nutrition.Food, Nutrition: nutrition},
+			Score:              row.Score,
+		})
+	}
+	return results, nil
 }
 
-// SearchFood implements ScanFoodRepository.
-func (s *scanFoodRepository) SearchFoodFromDB(name string) (*models.FoodWithNutritions, error) {
-	var foodWithNutrition models.FoodWithNutritions
-
-	food := s.db.Where("foods.name = ?", name).First(&foodWithNutrition.Food)
-	if food.Error != nil {
-		return nil, food.Error
+// SearchFoodAPI implements ScanFoodRepository.
+// Foods are ranked by data type, lab analysed Foundation and SR Legacy foods first, and keep the
+// relevance order of the USDA API within a data type. No foods return ErrFoodNotFound.
+func (s *scanFoodRepository) SearchFoodAPI(query string, limit int) ([]dto.FoodNutritionResponse, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("pageSize", strconv.Itoa(limit))
+
+	var result dto.FoodNutritionResponseClient
+	if err := s.getUSDA("/foods/search", params, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Foods) == 0 {
+		return nil, apperrors.ErrFoodNotFound()
 	}
 
-	nutritions := s.db.Where("food_id = ?", foodWithNutrition.Food.ID).First(&foodWithNutrition.Nutrition)
-	if nutritions.Error != nil {
-		return nil, nutritions.Error
+	foods := make([]dto.FoodNutritionResponse, 0, len(result.Foods))
+	for _, food := range result.Foods {
+		var nutrients usdaNutrients
+		for _, nutrient := range food.FoodNutrients {
+			nutrients.add(nutrient.NutrientName, nutrient.NutrientNumber, nutrient.UnitName, nutrient.Value)
+		}
+		foods = append(foods, nutrients.toResponse(dto.FoodNutritionResponse{
+			FdcID:         food.FdcID,
+			Name:          food.Description,
+			DataType:      food.DataType,
+			Brand:         food.BrandOwner,
+			Category:      food.FoodCategory,
+			ServingWeight: servingWeight(food.ServingSize, food.ServingSizeUnit),
+		}))
 	}
 
-	return &foodWithNutrition, nil
+	sort.SliceStable(foods, func(i, j int) bool {
+		return usdaDataTypeRank(foods[i].DataType) < usdaDataTypeRank(foods[j].DataType)
+	})
+	return foods, nil
 }
 
-// SearchFoodAPI implements ScanFoodRepository.
-func (s *scanFoodRepository) SearchFoodAPI(foodName string) (*dto.FoodNutritionResponse, error) {
-	url := fmt.Sprintf("https://api.nal.usda.gov/fdc/v1/foods/search?api_key=%s&query=%s", s.apiKey, strings.ReplaceAll(foodName, " ", "%20"))
-	resp, err := s.httpClient.Get(url)
-	if err != nil {
-		log.Fatal("Failed to fetch data:", err)
-	}
-
-	var food dto.FoodNutritionResponseClient
-	err = json.NewDecoder(resp.Body).Decode(&food)
-	if err != nil {
-		fmt.Println("Error decoding JSON:", err)
+// GetFoodAPI implements ScanFoodRepository.
+// Unknown foods return ErrFoodNotFound.
+func (s *scanFoodRepository) GetFoodAPI(fdcID int) (*dto.FoodNutritionResponse, error) {
+	var food dto.USDAFoodDetailClient
+	if err := s.getUSDA(fmt.Sprintf("/food/%d", fdcID), url.Values{}, &food); err != nil {
+		var statusErr *USDAStatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+			return nil, apperrors.ErrFoodNotFound()
+		}
 		return nil, err
 	}
 
-	// if no food data found.
-	if len(food.Foods) == 0 {
-		return nil, errors.New("food not found")
+	var nutrients usdaNutrients
+	for _, nutrient := range food.FoodNutrients {
+		nutrients.add(nutrient.Nutrient.Name, nutrient.Nutrient.Number, nutrient.Nutrient.UnitName, nutrient.Amount)
 	}
+	data := nutrients.toResponse(dto.FoodNutritionResponse{
+		FdcID:         food.FdcID,
+		Name:          food.Description,
+		DataType:      food.DataType,
+		Brand:         food.BrandOwner,
+		ServingWeight: servingWeight(food.ServingSize, food.ServingSizeUnit),
+	})
+	if food.FoodCategory != nil {
+		data.Category = food.FoodCategory.Description
+	}
+	return &data, nil
+}
 
-	// Extract the first food data.
-	foodData := food.Foods[0]
+// getUSDA calls the USDA API and decodes its JSON answer into result
+func (s *scanFoodRepository) getUSDA(path string, params url.Values, result interface{}) error {
+	// the key is sent as a header, errors of the http client contain the URL and are logged
+	endpoint := usdaBaseURL + path + "?" + params.Encode()
 
-	// Nutrient extraction using a map
-	targetNutrients := map[string]*float64{
-		"Energy":                      new(float64),
-		"Protein":                     new(float64),
-		"Total lipid (fat)":           new(float64),
-		"Carbohydrate, by difference": new(float64),
-		"Total Sugars":                new(float64),
-	}
+	return s.usdaUpstream.Execute(context.Background(), func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("X-Api-Key", s.apiKey)
+
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
 
-	// Extract the nutrient value.
-	for _, nutrient := range foodData.FoodNutrients {
-		if target, exists := targetNutrients[nutrient.NutrientName]; exists {
-			*target = nutrient.Value
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return &USDAStatusError{StatusCode: resp.StatusCode}
+		}
+
+		return json.NewDecoder(resp.Body).Decode(result)
+	})
+}
+
+// usdaNutrients collects the nutrients of a USDA food. The USDA API reports nutrients
+// per 100 gram, energy in kcal, kJ or both depending on the data type.
+type usdaNutrients struct {
+	kilocalories, kilojoules           *float64
+	protein, fat, carbohydrates, sugar float64
+}
+
+// add records a nutrient, matched by its USDA nutrient number
+func (n *usdaNutrients) add(name, number, unit string, value float64) {
+	switch number {
+	case "203":
+		n.protein = toGrams(value, unit)
+	case "204":
+		n.fat = toGrams(value, unit)
+	case "205":
+		n.carbohydrates = toGrams(value, unit)
+	case "269", "269.3":
+		// Foundation foods may report both total sugars and sugars NLEA, the first one wins
+		if n.sugar == 0 {
+			n.sugar = toGrams(value, unit)
+		}
+	default:
+		// Foundation foods report energy as Atwater factors instead of nutrient 208
+		if !strings.HasPrefix(name, "Energy") {
+			return
+		}
+		switch strings.ToUpper(unit) {
+		case "KCAL":
+			if n.kilocalories == nil || number == "208" {
+				n.kilocalories = &value
+			}
+		case "KJ":
+			if n.kilojoules == nil {
+				n.kilojoules = &value
+			}
 		}
 	}
+}
 
-	// Create the response data.
-	data := dto.FoodNutritionResponse{
-		Name:     foodName,
-		Calories: *targetNutrients["Energy"],
-		Protein:  *targetNutrients["Protein"],
-		Fat:      *targetNutrients["Total lipid (fat)"],
-		Carbs:    *targetNutrients["Carbohydrate, by difference"],
-		Sugar:    *targetNutrients["Total Sugars"],
-		Weight:   100,
+// toResponse fills the nutrition of a food, energy reported in kJ only is converted to kcal
+func (n *usdaNutrients) toResponse(food dto.FoodNutritionResponse) dto.FoodNutritionResponse {
+	switch {
+	case n.kilocalories != nil:
+		food.Calories = *n.kilocalories
+	case n.kilojoules != nil:
+		food.Calories = *n.kilojoules / kilojoulesPerKilocalorie
 	}
+	food.Protein = n.protein
+	food.Fat = n.fat
+	food.Carbs = n.carbohydrates
+	food.Sugar = n.sugar
+	food.Weight = 100
+	return food
+}
 
-	return &data, nil
+// toGrams converts a nutrient amount in G, MG or UG to gram
+func toGrams(value float64, unit string) float64 {
+	// lower case, the micro sign upper cases to a Greek capital mu
+	switch strings.ToLower(unit) {
+	case "mg":
+		return value / 1000
+	case "ug", "µg", "μg":
+		return value / 1000000
+	default:
+		return value
+	}
 }
 
-// CreateFood implements ScanFoodRepository.
-func (s *scanFoodRepository) CreateFood(food *models.Food) error {
-	result := s.db.Create(food)
-	if result.Error != nil {
-		return result.Error
+// servingWeight is the serving size of a branded food in gram, 0 when it is not given in gram
+func servingWeight(size float64, unit string) float64 {
+	switch strings.ToLower(unit) {
+	case "g", "grm", "gram":
+		return size
+	default:
+		return 0
 	}
+}
 
-	return nil
+// usdaDataTypeRank orders the USDA data types from most to least preferred
+func usdaDataTypeRank(dataType string) int {
+	switch dataType {
+	case "Foundation":
+		return 0
+	case "SR Legacy":
+		return 1
+	case "Survey (FNDDS)":
+		return 2
+	case "Branded":
+		return 3
+	default:
+		return 4
+	}
 }
 
-// CreateFoodNutrition implements ScanFoodRepository.
-func (s *scanFoodRepository) CreateFoodNutrition(foodNutrition *models.FoodNutrition) error {
-	result := s.db.Create(foodNutrition)
-	if result.Error != nil {
-		return result.Error
+// GetFoodByFdcID implements ScanFoodRepository.
+// Finds the food imported from a USDA food, with its nutrition.
+func (s *scanFoodRepository) GetFoodByFdcID(fdcID int) (*models.FoodWithNutritions, error) {
+	var foodWithNutrition models.FoodWithNutritions
+	if err := s.db.Where("fdc_id = ?", fdcID).First(&foodWithNutrition.Food).Error; err != nil {
+		return nil, err
+	}
+	if err := s.db.Where("food_id = ?", foodWithNutrition.Food.ID).First(&foodWithNutrition.Nutrition).Error; err != nil {
+		return nil, err
 	}
+	return &foodWithNutrition, nil
+}
 
-	return nil
+// CreateFoodWithNutrition implements ScanFoodRepository.
+// The food and its nutrition are created together, a food is never left without nutrition.
+func (s *scanFoodRepository) CreateFoodWithNutrition(food *models.Food, nutrition *models.FoodNutrition) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(food).Error; err != nil {
+			return err
+		}
+		nutrition.FoodID = food.ID
+		return tx.Omit(clause.Associations).Create(nutrition).Error
+	})
 }
 
 // GetFoodIDs implements ScanFoodRepository.
-func (s *scanFoodRepository) GetFoodIDs(foodNames *[]dto.ScanFood) (map[string]uint, error) {
-	var foods []models.Food
+// The result is keyed by the requested name, names are matched against food names and aliases.
+// Foods with the same name are picked by foodNamePriority, the custom foods of the user, then catalog foods.
+func (s *scanFoodRepository) GetFoodIDs(foodNames *[]dto.ScanFood, userID string) (map[string]uint, error) {
 	var names []string
 
 	// Ekstrak nama makanan dari slice dto.ScanFood
 	for _, food := range *foodNames {
-		names = append(names, food.Name)
+		names = append(names, strings.ToLower(strings.TrimSpace(food.Name)))
 	}
 
 	// Query untuk mengambil ID berdasarkan nama makanan
-	if err := s.db.Where("name IN ?", names).Find(&foods).Error; err != nil {
+	var foods []models.Food
+	if err := s.db.Scopes(visibleFoods(userID), foodNamePriority(userID)).Where("LOWER(name) IN ?", names).Find(&foods).Error; err != nil {
 		return nil, err
 	}
 
+	// Query untuk mengambil ID berdasarkan alias makanan
+	var aliases []models.FoodAlias
+	if err := s.db.Joins("JOIN foods ON foods.id = food_aliases.food_id").Scopes(visibleFoods(userID)).
+		Where("LOWER(alias) IN ?", names).Find(&aliases).Error; err != nil {
+		return nil, err
+	}
+
+	// Nama makanan lebih diutamakan daripada alias, makanan pertama per nama yang dipakai
+	idByName := make(map[string]uint)
+	for _, food := range foods {
+		if _, found := idByName[strings.ToLower(food.Name)]; !found {
+			idByName[strings.ToLower(food.Name)] = food.ID
+		}
+	}
+	for _, alias := range aliases {
+		if _, found := idByName[strings.ToLower(alias.Alias)]; !found {
+			idByName[strings.ToLower(alias.Alias)] = alias.FoodID
+		}
+	}
+
 	// Buat map nama makanan ke ID
 	foodMap := make(map[string]uint)
-	for _, food := range foods {
-		foodMap[food.Name] = food.ID
+	for _, food := range *foodNames {
+		if id, found := idByName[strings.ToLower(strings.TrimSpace(food.Name))]; found {
+			foodMap[food.Name] = id
+		}
 	}
 
 	return foodMap, nil
@@ -189,3 +464,50 @@ func (s *scanFoodRepository) SaveUserFoodHistory(food *[]models.UserFoodHistory)
 
 	return nil
 }
+
+// RecordUnmatchedLabel implements ScanFoodRepository.
+// Labels are counted, a label seen again only increments its count.
+func (s *scanFoodRepository) RecordUnmatchedLabel(label string) error {
+	now := time.Now()
+	return s.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "label"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"count":        gorm.Expr("unmatched_scan_labels.count + 1"),
+			"last_seen_at": now,
+			"updated_at":   now,
+		}),
+	}).Create(&models.UnmatchedScanLabel{
+		Label:      strings.ToLower(strings.TrimSpace(label)),
+		Count:      1,
+		LastSeenAt: now,
+	}).Error
+}
+
+// CreateScanSession implements ScanFoodRepository.
+func (s *scanFoodRepository) CreateScanSession(session *models.ScanSession) error {
+	return s.db.Create(session).Error
+}
+
+// GetScanSessionByID implements ScanFoodRepository.
+func (s *scanFoodRepository) GetScanSessionByID(id uint) (*models.ScanSession, error) {
+	var session models.ScanSession
+	if err := s.db.First(&session, id).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// UpdateScanSession implements ScanFoodRepository.
+func (s *scanFoodRepository) UpdateScanSession(session *models.ScanSession) error {
+	return s.db.Save(session).Error
+}
+
+// FailStaleScanSessions implements ScanFoodRepository.
+// Pending and processing sessions not updated since before are marked as failed,
+// it returns the number of sessions marked.
+func (s *scanFoodRepository) FailStaleScanSessions(before time.Time, message string) (int64, error) {
+	result := s.db.Model(&models.ScanSession{}).
+		Where("status IN ? AND updated_at < ?", []models.ScanStatus{models.ScanStatusPending, models.ScanStatusProcessing}, before).
+		Updates(map[string]interface{}{"status": models.ScanStatusFailed, "error": message})
+	return result.RowsAffected, result.Error
+}
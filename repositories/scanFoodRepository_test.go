@@ -0,0 +1,239 @@
+package repositories
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/resilience"
+)
+
+// redirectTransport sends every request to the test server instead of the USDA API
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (r redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = r.target.Scheme
+	req.URL.Host = r.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newUSDATestRepo creates a repository whose USDA calls are answered by handler
+func newUSDATestRepo(t *testing.T, handler http.HandlerFunc) ScanFoodRepository {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	target, _ := url.Parse(server.URL)
+	httpClient := &http.Client{Transport: redirectTransport{target: target}}
+	upstream := resilience.Register(testUpstreamName(t), resilience.Settings{Retryable: IsRetryableUSDAError})
+	return NewScanFoodRepository(httpClient, nil, nil, upstream, nil, "test-key")
+}
+
+func TestUSDANutrients(t *testing.T) {
+	tests := []struct {
+		name      string
+		nutrients []dto.USDANutrientClient
+		want      [5]float64 // calories, protein, fat, carbohydrates, sugar
+	}{
+		{
+			name: "survey food",
+			nutrients: []dto.USDANutrientClient{
+				{NutrientName: "Protein", NutrientNumber: "203", UnitName: "G", Value: 25.0},
+				{NutrientName: "Total lipid (fat)", NutrientNumber: "204", UnitName: "G", Value: 7.5},
+				{NutrientName: "Carbohydrate, by difference", NutrientNumber: "205", UnitName: "G", Value: 0.0},
+				{NutrientName: "Sugars, total including NLEA", NutrientNumber: "269", UnitName: "G", Value: 0.2},
+				{NutrientName: "Energy", NutrientNumber: "208", UnitName: "KCAL", Value: 172.0},
+			},
+			want: [5]float64{172, 25, 7.5, 0, 0.2},
+		},
+		{
+			name: "foundation food with Atwater energy and sugars in mg",
+			nutrients: []dto.USDANutrientClient{
+				{NutrientName: "Energy (Atwater General Factors)", NutrientNumber: "957", UnitName: "KCAL", Value: 120.0},
+				{NutrientName: "Energy", NutrientNumber: "208", UnitName: "KCAL", Value: 118.0},
+				{NutrientName: "Energy (Atwater Specific Factors)", NutrientNumber: "958", UnitName: "KCAL", Value: 115.0},
+				{NutrientName: "Sugars, Total", NutrientNumber: "269.3", UnitName: "MG", Value: 1500.0},
+				{NutrientName: "Sugars, total including NLEA", NutrientNumber: "269", UnitName: "G", Value: 9.0},
+			},
+			want: [5]float64{118, 0, 0, 0, 1.5},
+		},
+		{
+			name: "energy in kJ only",
+			nutrients: []dto.USDANutrientClient{
+				{NutrientName: "Energy", NutrientNumber: "268", UnitName: "kJ", Value: 418.4},
+				{NutrientName: "Protein", NutrientNumber: "203", UnitName: "G", Value: 3.0},
+			},
+			want: [5]float64{100, 3, 0, 0, 0},
+		},
+		{
+			name: "kcal wins over kJ",
+			nutrients: []dto.USDANutrientClient{
+				{NutrientName: "Energy", NutrientNumber: "268", UnitName: "KJ", Value: 836.8},
+				{NutrientName: "Energy", NutrientNumber: "208", UnitName: "KCAL", Value: 150.0},
+			},
+			want: [5]float64{150, 0, 0, 0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var n usdaNutrients
+			for _, nutrient := range tt.nutrients {
+				n.add(nutrient.NutrientName, nutrient.NutrientNumber, nutrient.UnitName, nutrient.Value)
+			}
+			food := n.toResponse(dto.FoodNutritionResponse{})
+
+			got := [5]float64{food.Calories, food.Protein, food.Fat, food.Carbs, food.Sugar}
+			for i := range got {
+				if math.Abs(got[i]-tt.want[i]) > 1e-9 {
+					t.Fatalf("nutrition = %v, want %v", got, tt.want)
+				}
+			}
+			if food.Weight != 100 {
+				t.Errorf("Weight = %v, want 100", food.Weight)
+			}
+		})
+	}
+}
+
+func TestToGrams(t *testing.T) {
+	tests := []struct {
+		value float64
+		unit  string
+		want  float64
+	}{
+		{12, "G", 12},
+		{12, "g", 12},
+		{1500, "MG", 1.5},
+		{2500000, "UG", 2.5},
+		{2500000, "µg", 2.5},
+		{2500000, "μg", 2.5},
+	}
+	for _, tt := range tests {
+		if got := toGrams(tt.value, tt.unit); got != tt.want {
+			t.Errorf("toGrams(%v, %q) = %v, want %v", tt.value, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestServingWeight(t *testing.T) {
+	tests := []struct {
+		size float64
+		unit string
+		want float64
+	}{
+		{30, "g", 30},
+		{30, "GRM", 30},
+		{250, "ml", 0},
+		{1, "", 0},
+	}
+	for _, tt := range tests {
+		if got := servingWeight(tt.size, tt.unit); got != tt.want {
+			t.Errorf("servingWeight(%v, %q) = %v, want %v", tt.size, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestSearchFoodAPIRanksByDataType(t *testing.T) {
+	repo := newUSDATestRepo(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/fdc/v1/foods/search" || r.URL.Query().Get("query") != "rice" || r.Header.Get("X-Api-Key") != "test-key" || r.URL.Query().Has("api_key") {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"foods": [
+			{"fdcId": 1, "description": "RICE CRACKERS", "dataType": "Branded", "brandOwner": "Acme", "servingSize": 30, "servingSizeUnit": "g",
+				"foodNutrients": [{"nutrientName": "Energy", "nutrientNumber": "208", "unitName": "KCAL", "value": 400}]},
+			{"fdcId": 2, "description": "Rice, white, cooked", "dataType": "Survey (FNDDS)",
+				"foodNutrients": [{"nutrientName": "Energy", "nutrientNumber": "208", "unitName": "KCAL", "value": 130}]},
+			{"fdcId": 3, "description": "Rice, brown, raw", "dataType": "Foundation",
+				"foodNutrients": [{"nutrientName": "Energy (Atwater General Factors)", "nutrientNumber": "957", "unitName": "KCAL", "value": 367}]},
+			{"fdcId": 4, "description": "Rice, white, raw", "dataType": "SR Legacy",
+				"foodNutrients": [{"nutrientName": "Energy", "nutrientNumber": "268", "unitName": "kJ", "value": 1527.16}]},
+			{"fdcId": 5, "description": "Rice, wild, cooked", "dataType": "Survey (FNDDS)",
+				"foodNutrients": []}
+		]}`))
+	})
+
+	foods, err := repo.SearchFoodAPI("rice", 5)
+	if err != nil {
+		t.Fatalf("SearchFoodAPI() error = %v", err)
+	}
+
+	// data types are ranked, the USDA order is kept within a data type
+	wantIDs := []int{3, 4, 2, 5, 1}
+	if len(foods) != len(wantIDs) {
+		t.Fatalf("got %d foods, want %d", len(foods), len(wantIDs))
+	}
+	for i, id := range wantIDs {
+		if foods[i].FdcID != id {
+			t.Errorf("foods[%d].FdcID = %d, want %d", i, foods[i].FdcID, id)
+		}
+	}
+
+	if math.Abs(foods[1].Calories-365) > 1e-9 {
+		t.Errorf("kJ only calories = %v, want 365", foods[1].Calories)
+	}
+	if foods[4].Brand != "Acme" || foods[4].ServingWeight != 30 {
+		t.Errorf("branded food = %+v, want brand Acme and a 30 g serving", foods[4])
+	}
+}
+
+func TestSearchFoodAPINotFound(t *testing.T) {
+	repo := newUSDATestRepo(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"foods": []}`))
+	})
+
+	if _, err := repo.SearchFoodAPI("nothing", 5); err == nil {
+		t.Error("SearchFoodAPI() error = nil, want food not found")
+	}
+}
+
+func TestGetFoodAPI(t *testing.T) {
+	repo := newUSDATestRepo(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/fdc/v1/food/42" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"fdcId": 42, "description": "Tempeh", "dataType": "SR Legacy",
+			"foodCategory": {"description": "Legumes and Legume Products"},
+			"foodNutrients": [
+				{"nutrient": {"name": "Energy", "number": "208", "unitName": "kcal"}, "amount": 192},
+				{"nutrient": {"name": "Protein", "number": "203", "unitName": "g"}, "amount": 20.3}
+			]}`))
+	})
+
+	food, err := repo.GetFoodAPI(42)
+	if err != nil {
+		t.Fatalf("GetFoodAPI() error = %v", err)
+	}
+	if food.Name != "Tempeh" || food.Calories != 192 || food.Protein != 20.3 || food.Category != "Legumes and Legume Products" {
+		t.Errorf("GetFoodAPI() = %+v", food)
+	}
+
+	if _, err := repo.GetFoodAPI(7); err == nil {
+		t.Error("GetFoodAPI() of an unknown food error = nil, want food not found")
+	}
+}
+
+func TestIsRetryableUSDAError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&USDAStatusError{StatusCode: http.StatusTooManyRequests}, true},
+		{&USDAStatusError{StatusCode: http.StatusBadGateway}, true},
+		{&USDAStatusError{StatusCode: http.StatusNotFound}, false},
+		{&USDAStatusError{StatusCode: http.StatusForbidden}, false},
+	}
+	for _, tt := range tests {
+		if got := IsRetryableUSDAError(tt.err); got != tt.want {
+			t.Errorf("IsRetryableUSDAError(%v) = %t, want %t", tt.err, got, tt.want)
+		}
+	}
+}
@@ -9,7 +9,7 @@ import (
 // UserRepository is a contract of user repository
 type UserRepository interface {
 	Update(user *models.User) error
-	GetFoodHistory(userID string) ([]dto.FoodHistoryByDate, error)
+	GetFoodHistory(userID, lang string) ([]dto.FoodHistoryByDate, error)
 	GetDailyNutrition(userID string) (*dto.DailyNutrition, error)
 }
 
@@ -38,7 +38,8 @@ func (r *userRepository) Update(user *models.User) error {
 }
 
 // GetFoodHistory retrieves food history for a user with pagination.
-func (r *userRepository) GetFoodHistory(userID string) ([]dto.FoodHistoryByDate, error) {
+// Food names are English, for other languages the first alias in that language is used when there is one.
+func (r *userRepository) GetFoodHistory(userID, lang string) ([]dto.FoodHistoryByDate, error) {
 	var foodHistory []dto.FoodHistoryByDate
 
 	// Get food history data with the given page and page size
@@ -47,8 +48,17 @@ func (r *userRepository) GetFoodHistory(userID string) ([]dto.FoodHistoryByDate,
     user_food_histories.id AS id,
     DATE(user_food_histories.created_at) AS date,
     SUM(user_food_histories.unit) AS total_units,
-    foods.name AS food_name,
-    food_nutritions.calories * user_food_histories.unit AS calories,
+    COALESCE((
+        SELECT food_aliases.alias FROM food_aliases
+        WHERE food_aliases.food_id = user_food_histories.food_id
+            AND food_aliases.language = ? AND food_aliases.language <> 'en'
+        ORDER BY food_aliases.id LIMIT 1
+    ), foods.name) AS food_name,
+    CASE 
+        WHEN user_food_histories.weight IS NOT NULL AND user_food_histories.weight > 0 
+        THEN food_nutritions.calories * (user_food_histories.weight / COALESCE(NULLIF(food_nutritions.weight, 0), 100.0))
+        ELSE food_nutritions.calories * user_food_histories.unit
+    END AS calories,
     TO_CHAR(user_food_histories.created_at, 'HH24:MI') AS time
 FROM 
     user_food_histories
@@ -63,11 +73,12 @@ GROUP BY
     user_food_histories.id, 
     foods.name, 
     food_nutritions.calories, 
+    food_nutritions.weight, 
     TO_CHAR(user_food_histories.created_at, 'HH24:MI'),
     user_food_histories.created_at
 ORDER BY 
     DATE(user_food_histories.created_at) DESC,
-    user_food_histories.created_at DESC;`, userID).
+    user_food_histories.created_at DESC;`, lang, userID).
 		Scan(&foodHistory).Error
 
 	if err != nil {
@@ -87,7 +98,7 @@ func (r *userRepository) GetDailyNutrition(userID string) (*dto.DailyNutrition,
         SUM(
             CASE 
                 WHEN user_food_histories.weight IS NOT NULL AND user_food_histories.weight > 0 
-                THEN food_nutritions.calories * (user_food_histories.weight / 100.0)
+                THEN food_nutritions.calories * (user_food_histories.weight / COALESCE(NULLIF(food_nutritions.weight, 0), 100.0))
                 ELSE food_nutritions.calories * user_food_histories.unit
             END
         ), 1
@@ -96,7 +107,7 @@ func (r *userRepository) GetDailyNutrition(userID string) (*dto.DailyNutrition,
         SUM(
             CASE 
                 WHEN user_food_histories.weight IS NOT NULL AND user_food_histories.weight > 0 
-                THEN food_nutritions.sugar * (user_food_histories.weight / 100.0)
+                THEN food_nutritions.sugar * (user_food_histories.weight / COALESCE(NULLIF(food_nutritions.weight, 0), 100.0))
                 ELSE food_nutritions.sugar * user_food_histories.unit
             END
         ), 1
@@ -105,7 +116,7 @@ func (r *userRepository) GetDailyNutrition(userID string) (*dto.DailyNutrition,
         SUM(
             CASE 
                 WHEN user_food_histories.weight IS NOT NULL AND user_food_histories.weight > 0 
-                THEN food_nutritions.carbohydrates * (user_food_histories.weight / 100.0)
+                THEN food_nutritions.carbohydrates * (user_food_histories.weight / COALESCE(NULLIF(food_nutritions.weight, 0), 100.0))
                 ELSE food_nutritions.carbohydrates * user_food_histories.unit
             END
         ), 1
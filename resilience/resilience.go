@@ -0,0 +1,312 @@
+// Package resilience protects calls to external services with per attempt
+// timeouts, bounded retries with exponential backoff and a circuit breaker.
+package resilience
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when a call is rejected because the upstream circuit is open.
+var ErrCircuitOpen = errors.New("upstream temporarily unavailable: circuit breaker is open")
+
+// State is the state of a circuit breaker.
+type State int
+
+const (
+	StateClosed State = iota
+	StateOpen
+	StateHalfOpen
+)
+
+// String implements fmt.Stringer.
+func (s State) String() string {
+	switch s {
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// Settings configures how calls to an upstream are protected.
+type Settings struct {
+	// Timeout bounds a single attempt. Zero means no extra timeout.
+	Timeout time.Duration
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	// BaseBackoff is the wait before the first retry, doubled on each retry.
+	BaseBackoff time.Duration
+	// MaxBackoff caps the wait between retries.
+	MaxBackoff time.Duration
+	// FailureThreshold is the number of consecutive failed calls that opens the circuit.
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before a probe call is allowed.
+	OpenTimeout time.Duration
+	// Retryable reports whether an error is transient. Non retryable errors are
+	// returned immediately and do not count as upstream failures.
+	Retryable func(err error) bool
+}
+
+// Metrics is a snapshot of an upstream's circuit breaker.
+type Metrics struct {
+	Name                string    `json:"name"`
+	State               string    `json:"state"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	Requests            uint64    `json:"requests"`
+	Successes           uint64    `json:"successes"`
+	Failures            uint64    `json:"failures"`
+	Retries             uint64    `json:"retries"`
+	Rejected            uint64    `json:"rejected"`
+	StateChanges        uint64    `json:"state_changes"`
+	LastStateChange     time.Time `json:"last_state_change"`
+}
+
+// Upstream guards calls to a single external service.
+type Upstream struct {
+	name     string
+	settings Settings
+
+	mu            sync.Mutex
+	state         State
+	failures      int
+	openedAt      time.Time
+	probeInFlight bool
+	metrics       Metrics
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]*Upstream{}
+)
+
+// Register returns the upstream with the given name, creating it with the
+// given settings on first use so every caller shares one circuit breaker.
+func Register(name string, settings Settings) *Upstream {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if u, ok := registry[name]; ok {
+		return u
+	}
+
+	if settings.FailureThreshold <= 0 {
+		settings.FailureThreshold = 5
+	}
+	if settings.OpenTimeout <= 0 {
+		settings.OpenTimeout = 30 * time.Second
+	}
+	if settings.BaseBackoff <= 0 {
+		settings.BaseBackoff = 200 * time.Millisecond
+	}
+	if settings.MaxBackoff <= 0 {
+		settings.MaxBackoff = 2 * time.Second
+	}
+	if settings.Retryable == nil {
+		settings.Retryable = func(err error) bool { return true }
+	}
+
+	u := &Upstream{
+		name:     name,
+		settings: settings,
+		metrics:  Metrics{Name: name, LastStateChange: time.Now()},
+	}
+	registry[name] = u
+	return u
+}
+
+// Snapshot returns the metrics of every registered upstream sorted by name.
+func Snapshot() []Metrics {
+	registryMu.Lock()
+	upstreams := make([]*Upstream, 0, len(registry))
+	for _, u := range registry {
+		upstreams = append(upstreams, u)
+	}
+	registryMu.Unlock()
+
+	result := make([]Metrics, 0, len(upstreams))
+	for _, u := range upstreams {
+		result = append(result, u.Metrics())
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// Name returns the upstream name.
+func (u *Upstream) Name() string {
+	return u.name
+}
+
+// Metrics returns a snapshot of the upstream metrics.
+func (u *Upstream) Metrics() Metrics {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	m := u.metrics
+	m.State = u.currentState().String()
+	m.ConsecutiveFailures = u.failures
+	return m
+}
+
+// Execute runs fn with the upstream protections applied.
+func (u *Upstream) Execute(ctx context.Context, fn func(ctx context.Context) error) error {
+	return u.ExecuteWith(ctx, u.settings.Retryable, fn)
+}
+
+// ExecuteWith runs fn like Execute but only retries the errors accepted by retry,
+// for calls that are not idempotent. Errors still count as upstream failures as
+// decided by the Retryable setting.
+func (u *Upstream) ExecuteWith(ctx context.Context, retry func(err error) bool, fn func(ctx context.Context) error) error {
+	if !u.allow() {
+		return ErrCircuitOpen
+	}
+
+	var err error
+	for attempt := 0; attempt <= u.settings.MaxRetries; attempt++ {
+		if attempt > 0 {
+			u.mu.Lock()
+			u.metrics.Retries++
+			u.mu.Unlock()
+
+			if waitErr := sleep(ctx, u.backoff(attempt)); waitErr != nil {
+				err = waitErr
+				break
+			}
+		}
+
+		err = u.attempt(ctx, fn)
+		if err == nil {
+			u.onSuccess()
+			return nil
+		}
+		if !u.settings.Retryable(err) {
+			// the upstream answered, the request itself was wrong
+			u.onSuccess()
+			return err
+		}
+		log.Printf("resilience: %s attempt %d failed: %v", u.name, attempt+1, err)
+		if !retry(err) {
+			break
+		}
+	}
+
+	if ctx.Err() != nil {
+		// the caller gave up, this says nothing about the upstream health
+		u.release()
+		return err
+	}
+
+	u.onFailure()
+	return err
+}
+
+// attempt runs fn once with the per attempt timeout.
+func (u *Upstream) attempt(ctx context.Context, fn func(ctx context.Context) error) error {
+	if u.settings.Timeout <= 0 {
+		return fn(ctx)
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, u.settings.Timeout)
+	defer cancel()
+	return fn(attemptCtx)
+}
+
+// backoff returns the exponential wait before the given retry with some jitter.
+func (u *Upstream) backoff(attempt int) time.Duration {
+	wait := u.settings.BaseBackoff << (attempt - 1)
+	if wait <= 0 || wait > u.settings.MaxBackoff {
+		wait = u.settings.MaxBackoff
+	}
+	jitter := time.Duration(rand.Int63n(int64(wait)/2 + 1))
+	return wait/2 + jitter
+}
+
+// allow reports whether a call may go through and counts it.
+func (u *Upstream) allow() bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.metrics.Requests++
+	switch u.currentState() {
+	case StateOpen:
+		u.metrics.Rejected++
+		return false
+	case StateHalfOpen:
+		if u.probeInFlight {
+			u.metrics.Rejected++
+			return false
+		}
+		u.setState(StateHalfOpen)
+		u.probeInFlight = true
+	}
+	return true
+}
+
+// currentState resolves an expired open state to half-open. Callers must hold mu.
+func (u *Upstream) currentState() State {
+	if u.state == StateOpen && time.Since(u.openedAt) >= u.settings.OpenTimeout {
+		return StateHalfOpen
+	}
+	return u.state
+}
+
+func (u *Upstream) onSuccess() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.metrics.Successes++
+	u.failures = 0
+	u.probeInFlight = false
+	u.setState(StateClosed)
+}
+
+func (u *Upstream) onFailure() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.metrics.Failures++
+	u.failures++
+	if u.state == StateHalfOpen || u.failures >= u.settings.FailureThreshold {
+		u.openedAt = time.Now()
+		u.setState(StateOpen)
+	}
+	u.probeInFlight = false
+}
+
+// release frees the half-open probe slot without recording a result.
+func (u *Upstream) release() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.probeInFlight = false
+}
+
+// setState changes the state and records the transition. Callers must hold mu.
+func (u *Upstream) setState(state State) {
+	if u.state == state {
+		return
+	}
+	log.Printf("resilience: %s circuit %s -> %s", u.name, u.state, state)
+	u.state = state
+	u.metrics.StateChanges++
+	u.metrics.LastStateChange = time.Now()
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
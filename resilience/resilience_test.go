@@ -0,0 +1,213 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+var (
+	errTransient = errors.New("transient")
+	errBadInput  = errors.New("bad input")
+)
+
+// newTestUpstream registers a new upstream for the test with short waits,
+// the name is unique so repeated runs do not share a circuit breaker
+func newTestUpstream(t *testing.T, settings Settings) *Upstream {
+	t.Helper()
+	settings.BaseBackoff = time.Millisecond
+	settings.MaxBackoff = time.Millisecond
+	if settings.Retryable == nil {
+		settings.Retryable = func(err error) bool { return !errors.Is(err, errBadInput) }
+	}
+	return Register(fmt.Sprintf("%s-%d", t.Name(), time.Now().UnixNano()), settings)
+}
+
+func TestExecuteRetriesTransientErrors(t *testing.T) {
+	u := newTestUpstream(t, Settings{MaxRetries: 2, FailureThreshold: 5})
+
+	calls := 0
+	err := u.Execute(context.Background(), func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errTransient
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+
+	m := u.Metrics()
+	if m.Retries != 2 || m.Successes != 1 || m.Failures != 0 {
+		t.Errorf("metrics = %+v, want 2 retries, 1 success and no failures", m)
+	}
+}
+
+func TestExecuteDoesNotRetryNonRetryableErrors(t *testing.T) {
+	u := newTestUpstream(t, Settings{MaxRetries: 2, FailureThreshold: 1})
+
+	calls := 0
+	err := u.Execute(context.Background(), func(ctx context.Context) error {
+		calls++
+		return errBadInput
+	})
+	if !errors.Is(err, errBadInput) {
+		t.Fatalf("Execute() error = %v, want %v", err, errBadInput)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+	if state := u.Metrics().State; state != StateClosed.String() {
+		t.Errorf("state = %s, want closed, a bad request says nothing about the upstream", state)
+	}
+}
+
+func TestExecuteWithOnlyRetriesAcceptedErrors(t *testing.T) {
+	u := newTestUpstream(t, Settings{MaxRetries: 2, FailureThreshold: 5})
+
+	calls := 0
+	never := func(err error) bool { return false }
+	err := u.ExecuteWith(context.Background(), never, func(ctx context.Context) error {
+		calls++
+		return errTransient
+	})
+	if !errors.Is(err, errTransient) {
+		t.Fatalf("ExecuteWith() error = %v, want %v", err, errTransient)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+
+	m := u.Metrics()
+	if m.Failures != 1 || m.ConsecutiveFailures != 1 {
+		t.Errorf("metrics = %+v, want the failure counted", m)
+	}
+}
+
+func TestBreakerOpensAfterThreshold(t *testing.T) {
+	u := newTestUpstream(t, Settings{FailureThreshold: 2, OpenTimeout: time.Hour})
+	fail := func(ctx context.Context) error { return errTransient }
+
+	for i := 0; i < 2; i++ {
+		if err := u.Execute(context.Background(), fail); !errors.Is(err, errTransient) {
+			t.Fatalf("call %d error = %v, want %v", i+1, err, errTransient)
+		}
+	}
+
+	calls := 0
+	err := u.Execute(context.Background(), func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Execute() error = %v, want %v", err, ErrCircuitOpen)
+	}
+	if calls != 0 {
+		t.Errorf("calls = %d, want 0 while the circuit is open", calls)
+	}
+
+	m := u.Metrics()
+	if m.State != StateOpen.String() || m.Rejected != 1 {
+		t.Errorf("metrics = %+v, want an open circuit with 1 rejected call", m)
+	}
+}
+
+func TestBreakerHalfOpenProbe(t *testing.T) {
+	u := newTestUpstream(t, Settings{FailureThreshold: 1, OpenTimeout: 20 * time.Millisecond})
+	fail := func(ctx context.Context) error { return errTransient }
+
+	_ = u.Execute(context.Background(), fail)
+	if state := u.Metrics().State; state != StateOpen.String() {
+		t.Fatalf("state = %s, want open", state)
+	}
+
+	// a failed probe opens the circuit again
+	time.Sleep(30 * time.Millisecond)
+	if state := u.Metrics().State; state != StateHalfOpen.String() {
+		t.Fatalf("state = %s, want half-open after the open timeout", state)
+	}
+	if err := u.Execute(context.Background(), fail); !errors.Is(err, errTransient) {
+		t.Fatalf("probe error = %v, want %v", err, errTransient)
+	}
+	if state := u.Metrics().State; state != StateOpen.String() {
+		t.Fatalf("state = %s, want open after a failed probe", state)
+	}
+
+	// a successful probe closes it
+	time.Sleep(30 * time.Millisecond)
+	if err := u.Execute(context.Background(), func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatalf("probe error = %v, want nil", err)
+	}
+	if state := u.Metrics().State; state != StateClosed.String() {
+		t.Errorf("state = %s, want closed after a successful probe", state)
+	}
+}
+
+func TestBreakerAllowsOneProbe(t *testing.T) {
+	u := newTestUpstream(t, Settings{FailureThreshold: 1, OpenTimeout: 10 * time.Millisecond})
+	_ = u.Execute(context.Background(), func(ctx context.Context) error { return errTransient })
+	time.Sleep(20 * time.Millisecond)
+
+	probing := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- u.Execute(context.Background(), func(ctx context.Context) error {
+			close(probing)
+			time.Sleep(20 * time.Millisecond)
+			return nil
+		})
+	}()
+	<-probing
+
+	if err := u.Execute(context.Background(), func(ctx context.Context) error { return nil }); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("second call error = %v, want %v while the probe is in flight", err, ErrCircuitOpen)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("probe error = %v, want nil", err)
+	}
+}
+
+func TestExecuteCanceledContextIsNotAFailure(t *testing.T) {
+	u := newTestUpstream(t, Settings{MaxRetries: 2, FailureThreshold: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := u.Execute(ctx, func(ctx context.Context) error {
+		cancel()
+		return errTransient
+	})
+	if err == nil {
+		t.Fatal("Execute() error = nil, want an error")
+	}
+
+	m := u.Metrics()
+	if m.State != StateClosed.String() || m.Failures != 0 {
+		t.Errorf("metrics = %+v, want a closed circuit without failures", m)
+	}
+}
+
+func TestExecuteAttemptTimeout(t *testing.T) {
+	u := newTestUpstream(t, Settings{Timeout: 10 * time.Millisecond, FailureThreshold: 5})
+
+	err := u.Execute(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Execute() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRegisterReturnsSharedUpstream(t *testing.T) {
+	name := fmt.Sprintf("%s-%d", t.Name(), time.Now().UnixNano())
+	first := Register(name, Settings{FailureThreshold: 1})
+	second := Register(name, Settings{FailureThreshold: 10})
+	if first != second {
+		t.Error("Register() returned a new upstream for an existing name")
+	}
+}
@@ -0,0 +1,27 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/config"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/handlers"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/middleware"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/repositories"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/services"
+)
+
+func activityRouter(r *gin.RouterGroup) {
+	//initialize dependencies
+	activityRepo := repositories.NewActivityRepository(config.DB)
+	exerciseRepo := repositories.NewExerciseRepository(config.DB)
+	healthRepo := repositories.NewHealthProfileRepository(config.DB)
+	activityService := services.NewActivityService(activityRepo, exerciseRepo, healthRepo)
+	activityHandler := handlers.NewActivityHandler(activityService)
+
+	// user routes
+	prefix := r.Group("/activity")
+	prefix.Use(middleware.AuthMiddleware())
+	prefix.GET("/exercises", activityHandler.GetExercises)
+	prefix.POST("/", activityHandler.LogActivity)
+	prefix.GET("/", activityHandler.GetActivities)
+	prefix.DELETE("/:id", activityHandler.DeleteActivity)
+}
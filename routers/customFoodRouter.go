@@ -0,0 +1,25 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/config"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/handlers"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/middleware"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/repositories"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/services"
+)
+
+func customFoodRouter(r *gin.RouterGroup) {
+	//initialize dependencies
+	customFoodRepo := repositories.NewCustomFoodRepository(config.DB)
+	recipeRepo := repositories.NewRecipeRepository(config.DB)
+	customFoodService := services.NewCustomFoodService(customFoodRepo, recipeRepo)
+	customFoodHandler := handlers.NewCustomFoodHandler(customFoodService)
+
+	// user routes
+	prefix := r.Group("/food/custom")
+	prefix.Use(middleware.AuthMiddleware())
+	prefix.GET("/", customFoodHandler.GetCustomFoods)
+	prefix.POST("/", customFoodHandler.CreateCustomFood)
+	prefix.PUT("/:id", customFoodHandler.UpdateCustomFood)
+}
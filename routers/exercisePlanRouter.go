@@ -0,0 +1,32 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/config"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/handlers"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/middleware"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/repositories"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/services"
+)
+
+func exercisePlanRouter(r *gin.RouterGroup) {
+	//initialize dependencies
+	recomendationRepo := newRecomendationRepo()
+	healthRepo := repositories.NewHealthProfileRepository(config.DB)
+	authRepo := repositories.NewAuthRepository(config.DB)
+	exerciseRepo := repositories.NewExerciseRepository(config.DB)
+	foodTagRepo := repositories.NewFoodTagRepository(config.DB)
+	feedbackRepo := repositories.NewFeedbackRepository(config.DB)
+	scanRepo := repositories.NewScanFoodRepository(newUSDAHttpClient(), newMLClient(), mlUpstream(), usdaUpstream(), config.DB, config.ENV.USDA_API_KEY)
+	activityRepo := repositories.NewActivityRepository(config.DB)
+	planRepo := repositories.NewExercisePlanRepository(config.DB)
+	recomendationService := services.NewRecomendationService(recomendationRepo, healthRepo, authRepo, exerciseRepo, foodTagRepo, feedbackRepo, scanRepo)
+	exercisePlanService := services.NewExercisePlanService(planRepo, healthRepo, activityRepo, recomendationService)
+	exercisePlanHandler := handlers.NewExercisePlanHandler(exercisePlanService)
+
+	// user routes
+	prefix := r.Group("/exercise-plan")
+	prefix.Use(middleware.AuthMiddleware())
+	prefix.GET("/", exercisePlanHandler.GetPlan)
+	prefix.POST("/regenerate", exercisePlanHandler.RegeneratePlan)
+}
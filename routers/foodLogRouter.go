@@ -0,0 +1,26 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/config"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/handlers"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/middleware"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/repositories"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/services"
+)
+
+func foodLogRouter(r *gin.RouterGroup) {
+	//initialize dependencies
+	foodLogRepo := repositories.NewFoodLogRepository(config.DB)
+	foodLogService := services.NewFoodLogService(foodLogRepo)
+	foodLogHandler := handlers.NewFoodLogHandler(foodLogService)
+
+	// user routes
+	prefix := r.Group("/food")
+	prefix.Use(middleware.AuthMiddleware())
+	prefix.GET("/frequent", foodLogHandler.GetFrequentFoods)
+	prefix.POST("/quick-log", foodLogHandler.QuickLog)
+	prefix.POST("/copy", foodLogHandler.CopyFoodLog)
+	prefix.POST("/favorites", foodLogHandler.AddFavorite)
+	prefix.DELETE("/favorites/:food_id", foodLogHandler.RemoveFavorite)
+}
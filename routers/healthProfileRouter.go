@@ -1,8 +1,6 @@
 package routers
 
 import (
-	"net/http"
-
 	"github.com/gin-gonic/gin"
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/config"
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/handlers"
@@ -13,10 +11,9 @@ import (
 
 func healthRouter(r *gin.RouterGroup) {
 	//initialize dependencies
-	httpClient := http.Client{}
 	healthRepo := repositories.NewHealthProfileRepository(config.DB)
 	authRepo := repositories.NewAuthRepository(config.DB)
-	recomendRepo := repositories.NewRecomendationRepo(&httpClient)
+	recomendRepo := newRecomendationRepo()
 	healthService := services.NewHealthProfileService(healthRepo, authRepo, recomendRepo)
 	healthHandler := handlers.NewHealthProfileHandler(healthService)
 
@@ -0,0 +1,28 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/config"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/handlers"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/middleware"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/repositories"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/services"
+)
+
+func mealPlanRouter(r *gin.RouterGroup) {
+	//initialize dependencies
+	recomendationRepo := newRecomendationRepo()
+	healthRepo := repositories.NewHealthProfileRepository(config.DB)
+	authRepo := repositories.NewAuthRepository(config.DB)
+	mealPlanRepo := repositories.NewMealPlanRepository(config.DB)
+	foodTagRepo := repositories.NewFoodTagRepository(config.DB)
+	feedbackRepo := repositories.NewFeedbackRepository(config.DB)
+	mealPlanService := services.NewMealPlanService(mealPlanRepo, recomendationRepo, healthRepo, authRepo, foodTagRepo, feedbackRepo)
+	mealPlanHandler := handlers.NewMealPlanHandler(mealPlanService)
+
+	// user routes
+	prefix := r.Group("/meal-plan")
+	prefix.Use(middleware.AuthMiddleware())
+	prefix.GET("/", mealPlanHandler.GetMealPlan)
+	prefix.POST("/items/:id/swap", mealPlanHandler.SwapItem)
+}
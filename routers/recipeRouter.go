@@ -0,0 +1,26 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/config"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/handlers"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/middleware"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/repositories"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/services"
+)
+
+func recipeRouter(r *gin.RouterGroup) {
+	//initialize dependencies
+	recipeRepo := repositories.NewRecipeRepository(config.DB)
+	customFoodRepo := repositories.NewCustomFoodRepository(config.DB)
+	recipeService := services.NewRecipeService(recipeRepo, customFoodRepo)
+	recipeHandler := handlers.NewRecipeHandler(recipeService)
+
+	// user routes
+	prefix := r.Group("/recipes")
+	prefix.Use(middleware.AuthMiddleware())
+	prefix.GET("/", recipeHandler.GetRecipes)
+	prefix.POST("/", recipeHandler.CreateRecipe)
+	prefix.GET("/:id", recipeHandler.GetRecipe)
+	prefix.PUT("/:id", recipeHandler.UpdateRecipe)
+}
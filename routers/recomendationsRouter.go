@@ -1,8 +1,6 @@
 package routers
 
 import (
-	"net/http"
-
 	"github.com/gin-gonic/gin"
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/config"
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/handlers"
@@ -13,15 +11,20 @@ import (
 
 func recomendationRouter(r *gin.RouterGroup) {
 	//initialize dependencies
-	httpClient := http.Client{}
-	recomendationRepo := repositories.NewRecomendationRepo(&httpClient)
+	recomendationRepo := newRecomendationRepo()
 	healthRepo := repositories.NewHealthProfileRepository(config.DB)
 	authRepo := repositories.NewAuthRepository(config.DB)
-	recomendationService := services.NewRecomendationService(recomendationRepo, healthRepo, authRepo)
+	exerciseRepo := repositories.NewExerciseRepository(config.DB)
+	foodTagRepo := repositories.NewFoodTagRepository(config.DB)
+	feedbackRepo := repositories.NewFeedbackRepository(config.DB)
+	scanRepo := repositories.NewScanFoodRepository(newUSDAHttpClient(), newMLClient(), mlUpstream(), usdaUpstream(), config.DB, config.ENV.USDA_API_KEY)
+	recomendationService := services.NewRecomendationService(recomendationRepo, healthRepo, authRepo, exerciseRepo, foodTagRepo, feedbackRepo, scanRepo)
 	recomendationHandler := handlers.NewRecomendationHandler(recomendationService)
 	// user routes
 
 	prefix := r.Group("food-recomendation")
 	prefix.Use(middleware.AuthMiddleware())
 	prefix.GET("", recomendationHandler.GetRecomendations)
+	prefix.POST("/feedback", recomendationHandler.SaveFeedback)
+	prefix.POST("/ate", recomendationHandler.AteFood)
 }
@@ -2,8 +2,16 @@ package routers
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/resilience"
 )
 
+// Shutdown waits for the background work started by the routes, like queued scans, to finish
+func Shutdown() {
+	if scanPool != nil {
+		scanPool.Close()
+	}
+}
+
 // Routers is a function to define all the routes
 func Routers(r *gin.Engine) {
 	prefix := r.Group("/api/v1/")
@@ -12,8 +20,14 @@ func Routers(r *gin.Engine) {
 	healthRouter(prefix)
 	recomendationRouter(prefix)
 	scanFoodRouter(prefix)
+	customFoodRouter(prefix)
+	recipeRouter(prefix)
+	foodLogRouter(prefix)
 	minicourseRouter(prefix)
 	miniGroceryRouter(prefix)
+	activityRouter(prefix)
+	exercisePlanRouter(prefix)
+	mealPlanRouter(prefix)
 
 	// health check
 	r.GET("/health", func(c *gin.Context) {
@@ -21,4 +35,12 @@ func Routers(r *gin.Engine) {
 			"message": "OK",
 		})
 	})
+
+	// upstream circuit breaker state
+	r.GET("/health/upstreams", func(c *gin.Context) {
+		c.JSON(200, gin.H{
+			"status": true,
+			"data":   resilience.Snapshot(),
+		})
+	})
 }
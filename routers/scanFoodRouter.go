@@ -1,7 +1,8 @@
 package routers
 
 import (
-	"net/http"
+	"log"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/config"
@@ -13,15 +14,27 @@ import (
 
 func scanFoodRouter(r *gin.RouterGroup) {
 	//initialize dependencies
-	repo := repositories.NewScanFoodRepository(&http.Client{}, config.DB, config.ENV.USDA_API_KEY)
+	repo := repositories.NewScanFoodRepository(newUSDAHttpClient(), newMLClient(), mlUpstream(), usdaUpstream(), config.DB, config.ENV.USDA_API_KEY)
 	storageRepo := repositories.NewStorageBucketService(config.Client)
-	service := services.NewScanFoodService(repo, storageRepo)
+	catalogRepo := repositories.NewCachedFoodCatalogRepository(repositories.NewFoodCatalogRepository(config.DB), 10*time.Minute)
+	productRepo := repositories.NewProductRepository(config.DB)
+	recipeRepo := repositories.NewRecipeRepository(config.DB)
+	service := services.NewScanFoodService(repo, storageRepo, catalogRepo, productRepo, recipeRepo, newScanPool())
 	scanFoodhandler := handlers.NewScanFoodHandler(service)
 
+	// scans queued before a restart were lost with the queue
+	if err := service.FailStaleScans(); err != nil {
+		log.Printf("Failed to fail stale scans: %v", err)
+	}
+
 	// user routes
 	prefix := r.Group("/food")
 	prefix.Use(middleware.AuthMiddleware())
 	prefix.POST("/scan", scanFoodhandler.ScanFood)
+	prefix.GET("/scan/:id", scanFoodhandler.GetScan)
 	prefix.POST("/find", scanFoodhandler.FindFood)
+	prefix.POST("/barcode", scanFoodhandler.FindFoodByBarcode)
+	prefix.GET("/search", scanFoodhandler.SearchFoodCandidates)
+	prefix.GET("/usda", scanFoodhandler.SearchUSDAFoods)
 	prefix.POST("/save", scanFoodhandler.SaveFood)
 }
@@ -0,0 +1,81 @@
+package routers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/config"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/mlclient"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/repositories"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/resilience"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/worker"
+)
+
+// newMLClient creates an ML service client with a bounded http client.
+// The http client timeout matches the longest endpoint timeout, so a single attempt never outlives it.
+func newMLClient() *mlclient.Client {
+	return mlclient.NewClient(config.ENV.ML_BASE_URL, &http.Client{Timeout: 30 * time.Second})
+}
+
+// newUSDAHttpClient creates the http client used for the USDA API.
+func newUSDAHttpClient() *http.Client {
+	return &http.Client{Timeout: 15 * time.Second}
+}
+
+// mlUpstream returns the shared circuit breaker for the ML service.
+func mlUpstream() *resilience.Upstream {
+	return resilience.Register("ml", resilience.Settings{
+		MaxRetries:       2,
+		BaseBackoff:      300 * time.Millisecond,
+		MaxBackoff:       2 * time.Second,
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+		Retryable:        mlclient.IsRetryable,
+	})
+}
+
+// usdaUpstream returns the shared circuit breaker for the USDA API.
+func usdaUpstream() *resilience.Upstream {
+	return resilience.Register("usda", resilience.Settings{
+		Timeout:          10 * time.Second,
+		MaxRetries:       2,
+		BaseBackoff:      300 * time.Millisecond,
+		MaxBackoff:       2 * time.Second,
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+		Retryable:        repositories.IsRetryableUSDAError,
+	})
+}
+
+// newRecomendationRepo creates the ML recommendation repository with caching.
+func newRecomendationRepo() repositories.RecomendationRepo {
+	ttl, err := time.ParseDuration(config.ENV.RECOMMENDATION_CACHE_TTL)
+	if err != nil {
+		log.Printf("Invalid RECOMMENDATION_CACHE_TTL %q, using 6h", config.ENV.RECOMMENDATION_CACHE_TTL)
+		ttl = 6 * time.Hour
+	}
+
+	repo := repositories.NewRecomendationRepo(newMLClient(), mlUpstream())
+	return repositories.NewCachedRecomendationRepo(repo, config.Cache, ttl, config.ENV.ML_MODEL_VERSION)
+}
+
+// scanPool runs the async scans, it is closed by Shutdown
+var scanPool *worker.Pool
+
+// newScanPool creates the worker pool for async scans, it bounds the concurrent calls to the ML service.
+func newScanPool() *worker.Pool {
+	workers, err := strconv.Atoi(config.ENV.SCAN_WORKERS)
+	if err != nil || workers <= 0 {
+		log.Printf("Invalid SCAN_WORKERS %q, using 4", config.ENV.SCAN_WORKERS)
+		workers = 4
+	}
+	queueSize, err := strconv.Atoi(config.ENV.SCAN_QUEUE_SIZE)
+	if err != nil || queueSize < 0 {
+		log.Printf("Invalid SCAN_QUEUE_SIZE %q, using 100", config.ENV.SCAN_QUEUE_SIZE)
+		queueSize = 100
+	}
+	scanPool = worker.NewPool(workers, queueSize)
+	return scanPool
+}
@@ -15,7 +15,8 @@ func userRouter(r *gin.RouterGroup) {
 	authRepo := repositories.NewAuthRepository(config.DB)
 	storageRepo := repositories.NewStorageBucketService(config.Client)
 	healthRepo := repositories.NewHealthProfileRepository(config.DB)
-	userService := services.NewUserService(userRepo, authRepo, storageRepo, healthRepo)
+	activityRepo := repositories.NewActivityRepository(config.DB)
+	userService := services.NewUserService(userRepo, authRepo, storageRepo, healthRepo, activityRepo)
 	storageService := services.NewStorageBucketService(storageRepo)
 	userHandler := handlers.NewUserHandler(userService, storageService)
 
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+	apperrors "github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	helper "github.com/rizkirmdhnnn/sweetlife-backend-go/helpers"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/repositories"
+	"gorm.io/gorm"
+)
+
+type ActivityService interface {
+	LogActivity(userID string, req *dto.CreateActivityRequest) (*dto.ActivityResponse, error)
+	GetActivities(userID, date string) (*dto.DailyActivityResponse, error)
+	DeleteActivity(userID string, id uint) error
+	GetExerciseCatalog(lang string) ([]dto.ExerciseCatalogResponse, error)
+}
+
+type activityService struct {
+	activityRepo repositories.ActivityRepository
+	exerciseRepo repositories.ExerciseRepository
+	healthRepo   repositories.HealthProfileRepository
+}
+
+func NewActivityService(activityRepo repositories.ActivityRepository, exerciseRepo repositories.ExerciseRepository, healthRepo repositories.HealthProfileRepository) ActivityService {
+	if activityRepo == nil {
+		panic("activityRepo cannot be nil")
+	}
+	if exerciseRepo == nil {
+		panic("exerciseRepo cannot be nil")
+	}
+	if healthRepo == nil {
+		panic("healthRepo cannot be nil")
+	}
+	return &activityService{
+		activityRepo: activityRepo,
+		exerciseRepo: exerciseRepo,
+		healthRepo:   healthRepo,
+	}
+}
+
+// LogActivity implements ActivityService.
+func (s *activityService) LogActivity(userID string, req *dto.CreateActivityRequest) (*dto.ActivityResponse, error) {
+	// 1. Get exercise from catalog
+	exercise, err := s.exerciseRepo.GetExerciseByID(req.ExerciseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrExerciseNotFound()
+		}
+		return nil, err
+	}
+
+	// 2. Body weight is needed to estimate burned calories
+	profile, err := s.healthRepo.GetHealthProfileByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrHealthProfileRequired()
+		}
+		return nil, err
+	}
+
+	if req.Intensity == "" {
+		req.Intensity = models.ModerateIntensity
+	}
+
+	caloriesBurned, err := helper.CalculateCaloriesBurned(exercise.MET, profile.Weight, req.Duration, req.Intensity)
+	if err != nil {
+		return nil, err
+	}
+
+	performedAt := time.Now()
+	if req.PerformedAt != nil {
+		if req.PerformedAt.After(time.Now()) {
+			return nil, apperrors.ErrActivityInFuture()
+		}
+		performedAt = *req.PerformedAt
+	}
+
+	// 3. Save activity
+	activity := models.ActivityLog{
+		UserID:         userID,
+		ExerciseID:     exercise.ID,
+		Duration:       req.Duration,
+		Intensity:      req.Intensity,
+		CaloriesBurned: caloriesBurned,
+		PerformedAt:    performedAt,
+	}
+	if err := s.activityRepo.CreateActivity(&activity); err != nil {
+		return nil, err
+	}
+	activity.Exercise = *exercise
+
+	resp := toActivityResponse(&activity)
+	return &resp, nil
+}
+
+// GetActivities implements ActivityService.
+func (s *activityService) GetActivities(userID, date string) (*dto.DailyActivityResponse, error) {
+	day := time.Now()
+	if date != "" {
+		parsed, err := helper.ParsedDate(date)
+		if err != nil {
+			return nil, apperrors.ErrInvalidDate()
+		}
+		day = time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 0, 0, 0, 0, time.Local)
+	}
+
+	activities, err := s.activityRepo.GetActivitiesByDate(userID, day)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.DailyActivityResponse{
+		Date:       day.Format("2006-01-02"),
+		Activities: []dto.ActivityResponse{},
+	}
+	for i := range activities {
+		resp.TotalDuration += activities[i].Duration
+		resp.TotalCaloriesBurned += activities[i].CaloriesBurned
+		resp.Activities = append(resp.Activities, toActivityResponse(&activities[i]))
+	}
+
+	return resp, nil
+}
+
+// DeleteActivity implements ActivityService.
+func (s *activityService) DeleteActivity(userID string, id uint) error {
+	activity, err := s.activityRepo.GetActivityByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrActivityNotFound()
+		}
+		return err
+	}
+
+	// users can only delete their own activities
+	if activity.UserID != userID {
+		return apperrors.ErrActivityNotFound()
+	}
+
+	return s.activityRepo.DeleteActivity(activity)
+}
+
+// GetExerciseCatalog implements ActivityService.
+func (s *activityService) GetExerciseCatalog(lang string) ([]dto.ExerciseCatalogResponse, error) {
+	exercises, err := s.exerciseRepo.GetAllExercises()
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := []dto.ExerciseCatalogResponse{}
+	for _, exercise := range exercises {
+		desc := exercise.Description
+		if lang == helper.LangIndonesian && exercise.DescriptionIndo != "" {
+			desc = exercise.DescriptionIndo
+		}
+		catalog = append(catalog, dto.ExerciseCatalogResponse{
+			ID:    exercise.ID,
+			Name:  exercise.Name,
+			Desc:  desc,
+			MET:   exercise.MET,
+			Image: exercise.Image,
+		})
+	}
+
+	return catalog, nil
+}
+
+// Helper function to map an activity log to response
+func toActivityResponse(activity *models.ActivityLog) dto.ActivityResponse {
+	return dto.ActivityResponse{
+		ID:             activity.ID,
+		ExerciseID:     activity.ExerciseID,
+		ExerciseName:   activity.Exercise.Name,
+		Image:          activity.Exercise.Image,
+		Duration:       activity.Duration,
+		Intensity:      activity.Intensity,
+		CaloriesBurned: activity.CaloriesBurned,
+		PerformedAt:    activity.PerformedAt,
+	}
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+	apperrors "github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/repositories"
+	"gorm.io/gorm"
+)
+
+type CustomFoodService interface {
+	CreateCustomFood(userID string, req *dto.CustomFoodRequest) (*dto.CustomFoodResponse, error)
+	GetCustomFoods(userID string) ([]dto.CustomFoodResponse, error)
+	UpdateCustomFood(userID string, id uint, req *dto.CustomFoodRequest) (*dto.CustomFoodResponse, error)
+}
+
+type customFoodService struct {
+	customFoodRepo repositories.CustomFoodRepository
+	recipeRepo     repositories.RecipeRepository
+}
+
+func NewCustomFoodService(customFoodRepo repositories.CustomFoodRepository, recipeRepo repositories.RecipeRepository) CustomFoodService {
+	if customFoodRepo == nil {
+		panic("customFoodRepo cannot be nil")
+	}
+	if recipeRepo == nil {
+		panic("recipeRepo cannot be nil")
+	}
+	return &customFoodService{
+		customFoodRepo: customFoodRepo,
+		recipeRepo:     recipeRepo,
+	}
+}
+
+// CreateCustomFood implements CustomFoodService.
+// Custom foods are private unless the user shares them, a user can not have two with the same name.
+func (s *customFoodService) CreateCustomFood(userID string, req *dto.CustomFoodRequest) (*dto.CustomFoodResponse, error) {
+	food := models.Food{OwnerID: &userID}
+	var nutrition models.FoodNutrition
+	if err := s.applyCustomFood(userID, &food, &nutrition, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.customFoodRepo.CreateCustomFood(&food, &nutrition); err != nil {
+		return nil, err
+	}
+	return toCustomFoodResponse(&food, &nutrition), nil
+}
+
+// GetCustomFoods implements CustomFoodService.
+func (s *customFoodService) GetCustomFoods(userID string) ([]dto.CustomFoodResponse, error) {
+	foods, err := s.customFoodRepo.GetCustomFoods(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.CustomFoodResponse, 0, len(foods))
+	for i := range foods {
+		result = append(result, *toCustomFoodResponse(&foods[i].Food, &foods[i].Nutrition))
+	}
+	return result, nil
+}
+
+// UpdateCustomFood implements CustomFoodService.
+// Only the owner can update a custom food, the food history keeps referring to it.
+// The recipes using the food are recalculated.
+func (s *customFoodService) UpdateCustomFood(userID string, id uint, req *dto.CustomFoodRequest) (*dto.CustomFoodResponse, error) {
+	existing, err := s.customFoodRepo.GetCustomFoodByID(id, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrCustomFoodNotFound()
+		}
+		return nil, err
+	}
+
+	food, nutrition := existing.Food, existing.Nutrition
+	if err := s.applyCustomFood(userID, &food, &nutrition, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.customFoodRepo.UpdateCustomFood(&food, &nutrition); err != nil {
+		return nil, err
+	}
+	if err := refreshRecipes(s.recipeRepo, food.ID); err != nil {
+		return nil, err
+	}
+	return toCustomFoodResponse(&food, &nutrition), nil
+}
+
+// applyCustomFood validates a custom food request and copies it to the food and its nutrition.
+// Nutrition is stored for its weight, 100 gram or one serving, like the other foods.
+func (s *customFoodService) applyCustomFood(userID string, food *models.Food, nutrition *models.FoodNutrition, req *dto.CustomFoodRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || req.Calories < 0 || req.Protein < 0 || req.Sugar < 0 || req.Carbohydrates < 0 || req.Fat < 0 {
+		return apperrors.ErrInvalidCustomFood()
+	}
+
+	weight := 100.0
+	switch req.Per {
+	case "", "100g":
+	case "serving":
+		if req.ServingWeight <= 0 {
+			return apperrors.ErrInvalidCustomFood()
+		}
+		weight = req.ServingWeight
+	default:
+		return apperrors.ErrInvalidCustomFood()
+	}
+
+	visibility := models.FoodVisibilityPrivate
+	switch models.FoodVisibility(req.Visibility) {
+	case "", models.FoodVisibilityPrivate:
+	case models.FoodVisibilityPublic:
+		visibility = models.FoodVisibilityPublic
+	default:
+		return apperrors.ErrInvalidCustomFood()
+	}
+
+	exists, err := s.customFoodRepo.CustomFoodNameExists(userID, name, food.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return apperrors.ErrCustomFoodExists()
+	}
+
+	food.Name = name
+	food.Visibility = visibility
+	nutrition.Weight = weight
+	nutrition.Calories = req.Calories
+	nutrition.Proteins = req.Protein
+	nutrition.Sugar = req.Sugar
+	nutrition.Carbohydrates = req.Carbohydrates
+	nutrition.Fat = req.Fat
+	return nil
+}
+
+func toCustomFoodResponse(food *models.Food, nutrition *models.FoodNutrition) *dto.CustomFoodResponse {
+	return &dto.CustomFoodResponse{
+		ID:            food.ID,
+		Name:          food.Name,
+		Visibility:    string(food.Visibility),
+		Weight:        nutrition.Weight,
+		Calories:      nutrition.Calories,
+		Protein:       nutrition.Proteins,
+		Sugar:         nutrition.Sugar,
+		Carbohydrates: nutrition.Carbohydrates,
+		Fat:           nutrition.Fat,
+		CreatedAt:     food.CreatedAt,
+		UpdatedAt:     food.UpdatedAt,
+	}
+}
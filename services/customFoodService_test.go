@@ -0,0 +1,161 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+	apperrors "github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"gorm.io/gorm"
+)
+
+const (
+	foodOwner  = "owner"
+	foodViewer = "viewer"
+)
+
+// memFoodStore keeps foods and recipes in memory for the recipe and custom food repositories
+type memFoodStore struct {
+	foods   map[uint]models.FoodWithNutritions
+	recipes map[uint]models.Recipe
+	nextID  uint
+}
+
+func newMemFoodStore() *memFoodStore {
+	return &memFoodStore{foods: make(map[uint]models.FoodWithNutritions), recipes: make(map[uint]models.Recipe)}
+}
+
+func (m *memFoodStore) id() uint {
+	m.nextID++
+	return m.nextID
+}
+
+func (m *memFoodStore) saveFood(food *models.Food, nutrition *models.FoodNutrition) {
+	if food.ID == 0 {
+		food.ID = m.id()
+	}
+	nutrition.FoodID = food.ID
+	m.foods[food.ID] = models.FoodWithNutritions{Food: *food, Nutrition: *nutrition}
+}
+
+// memCustomFoodRepo is a CustomFoodRepository on a memFoodStore
+type memCustomFoodRepo struct{ *memFoodStore }
+
+func (r memCustomFoodRepo) CreateCustomFood(food *models.Food, nutrition *models.FoodNutrition) error {
+	r.saveFood(food, nutrition)
+	return nil
+}
+
+func (r memCustomFoodRepo) UpdateCustomFood(food *models.Food, nutrition *models.FoodNutrition) error {
+	r.saveFood(food, nutrition)
+	return nil
+}
+
+func (r memCustomFoodRepo) GetCustomFoods(userID string) ([]models.FoodWithNutritions, error) {
+	var foods []models.FoodWithNutritions
+	for _, food := range r.foods {
+		if food.Food.OwnerID != nil && *food.Food.OwnerID == userID {
+			foods = append(foods, food)
+		}
+	}
+	return foods, nil
+}
+
+func (r memCustomFoodRepo) GetCustomFoodByID(id uint, userID string) (*models.FoodWithNutritions, error) {
+	food, found := r.foods[id]
+	if !found || food.Food.OwnerID == nil || *food.Food.OwnerID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &food, nil
+}
+
+func (r memCustomFoodRepo) CustomFoodNameExists(userID string, name string, excludeID uint) (bool, error) {
+	for id, food := range r.foods {
+		if id != excludeID && food.Food.OwnerID != nil && *food.Food.OwnerID == userID && strings.EqualFold(food.Food.Name, strings.TrimSpace(name)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestCreateCustomFood(t *testing.T) {
+	store := newMemFoodStore()
+	service := NewCustomFoodService(memCustomFoodRepo{store}, memRecipeRepo{store})
+
+	private, err := service.CreateCustomFood(foodOwner, &dto.CustomFoodRequest{Name: " Sambal ", Calories: 50})
+	if err != nil {
+		t.Fatalf("CreateCustomFood() error = %v", err)
+	}
+	if private.Name != "Sambal" || private.Visibility != string(models.FoodVisibilityPrivate) || private.Weight != 100 {
+		t.Errorf("CreateCustomFood() = %+v, want a private food per 100 g", private)
+	}
+	if owner := store.foods[private.ID].Food.OwnerID; owner == nil || *owner != foodOwner {
+		t.Errorf("custom food owner = %v, want %s", owner, foodOwner)
+	}
+
+	shared, err := service.CreateCustomFood(foodOwner, &dto.CustomFoodRequest{Name: "Keripik", Per: "serving", ServingWeight: 30, Calories: 150, Visibility: "public"})
+	if err != nil {
+		t.Fatalf("CreateCustomFood() error = %v", err)
+	}
+	if shared.Visibility != string(models.FoodVisibilityPublic) || shared.Weight != 30 {
+		t.Errorf("CreateCustomFood() = %+v, want a public food per 30 g serving", shared)
+	}
+
+	// names are unique per user only
+	if _, err := service.CreateCustomFood(foodViewer, &dto.CustomFoodRequest{Name: "sambal", Calories: 40}); err != nil {
+		t.Errorf("CreateCustomFood() with the name of a food of another user error = %v", err)
+	}
+}
+
+func TestCreateCustomFoodRejects(t *testing.T) {
+	store := newMemFoodStore()
+	service := NewCustomFoodService(memCustomFoodRepo{store}, memRecipeRepo{store})
+	if _, err := service.CreateCustomFood(foodOwner, &dto.CustomFoodRequest{Name: "Sambal", Calories: 50}); err != nil {
+		t.Fatalf("CreateCustomFood() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		req     dto.CustomFoodRequest
+		wantErr error
+	}{
+		{"blank name", dto.CustomFoodRequest{Name: "  "}, apperrors.ErrInvalidCustomFood()},
+		{"negative nutrition", dto.CustomFoodRequest{Name: "A", Fat: -1}, apperrors.ErrInvalidCustomFood()},
+		{"serving without weight", dto.CustomFoodRequest{Name: "B", Per: "serving"}, apperrors.ErrInvalidCustomFood()},
+		{"unknown visibility", dto.CustomFoodRequest{Name: "C", Visibility: "friends"}, apperrors.ErrInvalidCustomFood()},
+		{"same name", dto.CustomFoodRequest{Name: "SAMBAL"}, apperrors.ErrCustomFoodExists()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.CreateCustomFood(foodOwner, &tt.req)
+			if err == nil || err.Error() != tt.wantErr.Error() {
+				t.Errorf("CreateCustomFood() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateCustomFoodOnlyByOwner(t *testing.T) {
+	store := newMemFoodStore()
+	service := NewCustomFoodService(memCustomFoodRepo{store}, memRecipeRepo{store})
+	food, err := service.CreateCustomFood(foodOwner, &dto.CustomFoodRequest{Name: "Sambal", Calories: 50, Visibility: "public"})
+	if err != nil {
+		t.Fatalf("CreateCustomFood() error = %v", err)
+	}
+
+	// a shared food can be used by others, but not changed by them
+	req := &dto.CustomFoodRequest{Name: "Sambal", Calories: 10}
+	if _, err := service.UpdateCustomFood(foodViewer, food.ID, req); err == nil || err.Error() != apperrors.ErrCustomFoodNotFound().Error() {
+		t.Errorf("UpdateCustomFood() by another user error = %v, want %v", err, apperrors.ErrCustomFoodNotFound())
+	}
+
+	updated, err := service.UpdateCustomFood(foodOwner, food.ID, req)
+	if err != nil {
+		t.Fatalf("UpdateCustomFood() error = %v", err)
+	}
+	if updated.Calories != 10 || updated.Visibility != string(models.FoodVisibilityPrivate) {
+		t.Errorf("UpdateCustomFood() = %+v, want a private food with 10 kcal", updated)
+	}
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+	apperrors "github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	helper "github.com/rizkirmdhnnn/sweetlife-backend-go/helpers"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/repositories"
+	"gorm.io/gorm"
+)
+
+// defaultSessionDuration is used when the ML service does not return a duration (minutes)
+const defaultSessionDuration = 30
+
+type ExercisePlanService interface {
+	GetPlan(userID, lang string) (*dto.ExercisePlanResponse, error)
+	RegeneratePlan(userID, lang string) (*dto.ExercisePlanResponse, error)
+}
+
+type exercisePlanService struct {
+	planRepo             repositories.ExercisePlanRepository
+	healthRepo           repositories.HealthProfileRepository
+	activityRepo         repositories.ActivityRepository
+	recomendationService RecomendationService
+}
+
+func NewExercisePlanService(planRepo repositories.ExercisePlanRepository, healthRepo repositories.HealthProfileRepository, activityRepo repositories.ActivityRepository, recomendationService RecomendationService) ExercisePlanService {
+	if planRepo == nil {
+		panic("planRepo cannot be nil")
+	}
+	if recomendationService == nil {
+		panic("recomendationService cannot be nil")
+	}
+	return &exercisePlanService{
+		planRepo:             planRepo,
+		healthRepo:           healthRepo,
+		activityRepo:         activityRepo,
+		recomendationService: recomendationService,
+	}
+}
+
+// GetPlan implements ExercisePlanService.
+// The plan is regenerated when it belongs to a previous week or the health profile changed since it was generated.
+func (s *exercisePlanService) GetPlan(userID, lang string) (*dto.ExercisePlanResponse, error) {
+	profile, err := s.healthRepo.GetHealthProfileByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrHealthProfileRequired()
+		}
+		return nil, err
+	}
+
+	plan, err := s.planRepo.GetPlanByUserID(userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	currentWeek := helper.StartOfWeek(time.Now()).Format("2006-01-02")
+	if plan == nil || plan.WeekStart.Format("2006-01-02") != currentWeek || !plan.ProfileUpdatedAt.Equal(profile.UpdatedAt) {
+		plan, err = s.generatePlan(userID, lang, profile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.buildResponse(userID, plan)
+}
+
+// RegeneratePlan implements ExercisePlanService.
+func (s *exercisePlanService) RegeneratePlan(userID, lang string) (*dto.ExercisePlanResponse, error) {
+	profile, err := s.healthRepo.GetHealthProfileByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrHealthProfileRequired()
+		}
+		return nil, err
+	}
+
+	plan, err := s.generatePlan(userID, lang, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildResponse(userID, plan)
+}
+
+// generatePlan turns the ML exercise recommendation into sessions spread over the current week.
+func (s *exercisePlanService) generatePlan(userID, lang string, profile *models.HealthProfile) (*models.ExercisePlan, error) {
+	recommendation, err := s.recomendationService.GetExerciseRecomendations(userID, lang)
+	if err != nil {
+		return nil, err
+	}
+	if len(recommendation.ExerciseList) == 0 {
+		return nil, apperrors.ErrNoExerciseRecommendation()
+	}
+
+	duration := int(math.Round(recommendation.ExerciseDuration))
+	if duration <= 0 {
+		duration = defaultSessionDuration
+	}
+
+	weekStart := helper.StartOfWeek(time.Now())
+	plan := models.ExercisePlan{
+		UserID:           userID,
+		WeekStart:        weekStart,
+		CaloriesBurned:   recommendation.CaloriesBurned,
+		SessionDuration:  duration,
+		ProfileUpdatedAt: profile.UpdatedAt,
+	}
+
+	// spread the sessions evenly over the week and rotate the recommended exercises,
+	// the calories to burn are for the whole week and shared by the sessions
+	sessionCount := sessionsPerWeek(profile.ActivityLevel)
+	sessionCalories := math.Round(recommendation.CaloriesBurned/float64(sessionCount)*100) / 100
+	for i := 0; i < sessionCount; i++ {
+		exercise := recommendation.ExerciseList[i%len(recommendation.ExerciseList)]
+		session := models.ExercisePlanSession{
+			Date:           weekStart.AddDate(0, 0, i*7/sessionCount),
+			ExerciseName:   exercise.Name,
+			Duration:       duration,
+			TargetCalories: sessionCalories,
+		}
+		if exercise.ID != 0 {
+			exerciseID := exercise.ID
+			session.ExerciseID = &exerciseID
+		}
+		plan.Sessions = append(plan.Sessions, session)
+	}
+
+	if err := s.planRepo.ReplacePlan(&plan); err != nil {
+		return nil, err
+	}
+
+	// reload to get the exercise details of the sessions
+	return s.planRepo.GetPlanByUserID(userID)
+}
+
+// buildResponse maps a plan to response and tracks completion against the activity log.
+func (s *exercisePlanService) buildResponse(userID string, plan *models.ExercisePlan) (*dto.ExercisePlanResponse, error) {
+	weekStart, _ := helper.ParsedDate(plan.WeekStart.Format("2006-01-02"))
+	weekStart = time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day(), 0, 0, 0, 0, time.Local)
+	weekEnd := weekStart.AddDate(0, 0, 7)
+
+	activities, err := s.activityRepo.GetActivitiesBetween(userID, weekStart, weekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	// minutes done per day and exercise
+	doneMinutes := make(map[string]int)
+	for _, activity := range activities {
+		key := activity.PerformedAt.In(time.Local).Format("2006-01-02") + "|" + strings.ToLower(activity.Exercise.Name)
+		doneMinutes[key] += activity.Duration
+	}
+
+	resp := &dto.ExercisePlanResponse{
+		WeekStart:       weekStart.Format("2006-01-02"),
+		WeekEnd:         weekEnd.AddDate(0, 0, -1).Format("2006-01-02"),
+		CaloriesBurned:  plan.CaloriesBurned,
+		SessionDuration: plan.SessionDuration,
+		TotalSessions:   len(plan.Sessions),
+		Sessions:        []dto.ExercisePlanSessions{},
+	}
+
+	for _, session := range plan.Sessions {
+		date := session.Date.Format("2006-01-02")
+		key := date + "|" + strings.ToLower(session.ExerciseName)
+
+		// a session can only be completed once, consume the minutes used
+		completed := doneMinutes[key]
+		if completed > session.Duration {
+			completed = session.Duration
+		}
+		doneMinutes[key] -= completed
+
+		item := dto.ExercisePlanSessions{
+			ID:               session.ID,
+			Date:             date,
+			Day:              session.Date.Weekday().String(),
+			ExerciseID:       session.ExerciseID,
+			ExerciseName:     session.ExerciseName,
+			Duration:         session.Duration,
+			TargetCalories:   session.TargetCalories,
+			CompletedMinutes: completed,
+			Completed:        completed >= session.Duration,
+		}
+		if session.Exercise != nil {
+			item.Image = session.Exercise.Image
+		}
+		if item.Completed {
+			resp.CompletedSessions++
+		}
+
+		resp.Sessions = append(resp.Sessions, item)
+	}
+
+	return resp, nil
+}
+
+// sessionsPerWeek returns how many sessions to plan for an activity level
+func sessionsPerWeek(level models.ActivityLevel) int {
+	switch level {
+	case models.Moderate:
+		return 4
+	case models.Active:
+		return 5
+	case models.Extremely:
+		return 6
+	default:
+		return 3
+	}
+}
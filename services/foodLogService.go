@@ -0,0 +1,358 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+	apperrors "github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	helper "github.com/rizkirmdhnnn/sweetlife-backend-go/helpers"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/repositories"
+)
+
+const (
+	// frequentFoodsWindow is how far back logged foods count as frequent
+	frequentFoodsWindow = 90 * 24 * time.Hour
+	// defaultFrequentFoods and maxFrequentFoods bound the frequent foods besides the pinned ones
+	defaultFrequentFoods = 10
+	maxFrequentFoods     = 50
+)
+
+type FoodLogService interface {
+	GetFrequentFoods(userID string, limit int) ([]dto.FrequentFood, error)
+	AddFavorite(userID string, foodID uint) error
+	RemoveFavorite(userID string, foodID uint) error
+	QuickLog(userID string, req *dto.QuickLogRequest) (*dto.FoodLogEntry, error)
+	CopyFoodLog(userID string, req *dto.CopyFoodLogRequest) ([]dto.FoodLogEntry, error)
+}
+
+type foodLogService struct {
+	foodLogRepo repositories.FoodLogRepository
+}
+
+func NewFoodLogService(foodLogRepo repositories.FoodLogRepository) FoodLogService {
+	if foodLogRepo == nil {
+		panic("foodLogRepo cannot be nil")
+	}
+	return &foodLogService{
+		foodLogRepo: foodLogRepo,
+	}
+}
+
+// GetFrequentFoods implements FoodLogService.
+// Pinned foods come first in the order they were pinned, followed by the foods logged
+// most often in the last 90 days. Nutrition is given for the last logged portion.
+func (s *foodLogService) GetFrequentFoods(userID string, limit int) ([]dto.FrequentFood, error) {
+	if limit <= 0 || limit > maxFrequentFoods {
+		limit = defaultFrequentFoods
+	}
+
+	favorites, err := s.foodLogRepo.GetFavorites(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// pinned foods may be among the most frequent, ask for enough to fill the limit
+	stats, err := s.foodLogRepo.GetFoodLogStats(userID, time.Now().Add(-frequentFoodsWindow), limit+len(favorites))
+	if err != nil {
+		return nil, err
+	}
+
+	pinned := make(map[uint]bool, len(favorites))
+	ids := make([]uint, 0, len(favorites)+len(stats))
+	for _, favorite := range favorites {
+		pinned[favorite.FoodID] = true
+		ids = append(ids, favorite.FoodID)
+	}
+	statByFood := make(map[uint]models.FoodLogStat, len(stats))
+	for _, stat := range stats {
+		statByFood[stat.FoodID] = stat
+		if !pinned[stat.FoodID] {
+			ids = append(ids, stat.FoodID)
+		}
+	}
+
+	foods, err := s.getFoods(ids, userID)
+	if err != nil {
+		return nil, err
+	}
+	portions, err := s.foodLogRepo.GetLastPortions(userID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.FrequentFood, 0, len(ids))
+	frequent := 0
+	for _, id := range ids {
+		// foods that are no longer visible, e.g. a custom food made private by its owner, are left out
+		food, found := foods[id]
+		if !found {
+			continue
+		}
+		if !pinned[id] {
+			if frequent == limit {
+				break
+			}
+			frequent++
+		}
+
+		item := dto.FrequentFood{
+			FoodID: id,
+			Name:   food.Food.Name,
+			Pinned: pinned[id],
+			Unit:   1,
+		}
+		if stat, found := statByFood[id]; found {
+			lastLoggedAt := stat.LastLoggedAt
+			item.Count = stat.Count
+			item.LastLoggedAt = &lastLoggedAt
+		}
+
+		var weight *float64
+		if portion, found := portions[id]; found {
+			item.Unit = portion.Unit
+			weight = portion.Weight
+		}
+		nutrition := portionNutrition(item.Unit, weight, &food.Nutrition)
+		item.Weight = round2(nutrition.Weight)
+		item.Calories = round2(nutrition.Calories)
+		item.Protein = round2(nutrition.Proteins)
+		item.Sugar = round2(nutrition.Sugar)
+		item.Carbohydrates = round2(nutrition.Carbohydrates)
+		item.Fat = round2(nutrition.Fat)
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// AddFavorite implements FoodLogService.
+func (s *foodLogService) AddFavorite(userID string, foodID uint) error {
+	foods, err := s.getFoods([]uint{foodID}, userID)
+	if err != nil {
+		return err
+	}
+	if _, found := foods[foodID]; !found {
+		return apperrors.ErrFoodNotFound()
+	}
+	return s.foodLogRepo.AddFavorite(userID, foodID)
+}
+
+// RemoveFavorite implements FoodLogService.
+func (s *foodLogService) RemoveFavorite(userID string, foodID uint) error {
+	return s.foodLogRepo.RemoveFavorite(userID, foodID)
+}
+
+// QuickLog implements FoodLogService.
+// Without a unit or weight the food is logged with the portion it was last logged with,
+// or one unit when it was never logged before.
+func (s *foodLogService) QuickLog(userID string, req *dto.QuickLogRequest) (*dto.FoodLogEntry, error) {
+	foods, err := s.getFoods([]uint{req.FoodID}, userID)
+	if err != nil {
+		return nil, err
+	}
+	food, found := foods[req.FoodID]
+	if !found {
+		return nil, apperrors.ErrFoodNotFound()
+	}
+	meal, err := parseMeal(req.Meal)
+	if err != nil {
+		return nil, err
+	}
+
+	history := models.UserFoodHistory{
+		UserID: userID,
+		FoodID: req.FoodID,
+		Unit:   req.Unit,
+		Weight: req.Weight,
+		Meal:   meal,
+	}
+	if req.Unit <= 0 && (req.Weight == nil || *req.Weight <= 0) {
+		portions, err := s.foodLogRepo.GetLastPortions(userID, []uint{req.FoodID})
+		if err != nil {
+			return nil, err
+		}
+		if last, found := portions[req.FoodID]; found {
+			history.Unit = last.Unit
+			history.Weight = last.Weight
+		}
+	}
+	if history.Unit <= 0 {
+		history.Unit = 1
+	}
+	if history.Weight != nil && *history.Weight <= 0 {
+		history.Weight = nil
+	}
+
+	histories := []models.UserFoodHistory{history}
+	if err := s.foodLogRepo.CreateHistories(histories); err != nil {
+		return nil, err
+	}
+	return toFoodLogEntry(&histories[0], &food), nil
+}
+
+// CopyFoodLog implements FoodLogService.
+// The entries keep their food, portion, meal and time of day. Entries of foods the user can
+// no longer see, e.g. a custom food made private by its owner, are not copied.
+// Copying is idempotent, entries already on the target date are not copied again.
+// Entries logged before meals were recorded have no meal and are not found by a meal copy.
+func (s *foodLogService) CopyFoodLog(userID string, req *dto.CopyFoodLogRequest) ([]dto.FoodLogEntry, error) {
+	from, err := helper.ParsedDate(req.From)
+	if err != nil {
+		return nil, apperrors.ErrInvalidDate()
+	}
+	to := time.Now()
+	if req.To != "" {
+		to, err = helper.ParsedDate(req.To)
+		if err != nil {
+			return nil, apperrors.ErrInvalidDate()
+		}
+	}
+	meal, err := parseMeal(req.Meal)
+	if err != nil {
+		return nil, err
+	}
+
+	// dates are days in the local time zone, like the food history
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+	target := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.Local)
+	if start.Equal(target) {
+		return nil, apperrors.ErrCopySameDate()
+	}
+
+	sources, err := s.foodLogRepo.GetHistoriesBetween(userID, start, start.AddDate(0, 0, 1), meal)
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) == 0 {
+		return nil, apperrors.ErrNoFoodEntries()
+	}
+
+	existing, err := s.foodLogRepo.GetHistoriesBetween(userID, target, target.AddDate(0, 0, 1), meal)
+	if err != nil {
+		return nil, err
+	}
+	copied := make(map[string]bool, len(existing))
+	for i := range existing {
+		copied[copyKey(&existing[i])] = true
+	}
+
+	ids := make([]uint, 0, len(sources))
+	for _, source := range sources {
+		ids = append(ids, source.FoodID)
+	}
+	foods, err := s.getFoods(ids, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	histories := make([]models.UserFoodHistory, 0, len(sources))
+	visible := 0
+	for _, source := range sources {
+		if _, found := foods[source.FoodID]; !found {
+			continue
+		}
+		visible++
+		loggedAt := source.CreatedAt.In(time.Local)
+		history := models.UserFoodHistory{
+			UserID:    userID,
+			FoodID:    source.FoodID,
+			Unit:      source.Unit,
+			Weight:    source.Weight,
+			Meal:      source.Meal,
+			CreatedAt: time.Date(to.Year(), to.Month(), to.Day(), loggedAt.Hour(), loggedAt.Minute(), loggedAt.Second(), loggedAt.Nanosecond(), time.Local),
+		}
+		if copied[copyKey(&history)] {
+			continue
+		}
+		histories = append(histories, history)
+	}
+	if visible == 0 {
+		return nil, apperrors.ErrNoFoodEntries()
+	}
+
+	if len(histories) > 0 {
+		if err := s.foodLogRepo.CreateHistories(histories); err != nil {
+			return nil, err
+		}
+	}
+
+	entries := make([]dto.FoodLogEntry, 0, len(histories))
+	for i := range histories {
+		food := foods[histories[i].FoodID]
+		entries = append(entries, *toFoodLogEntry(&histories[i], &food))
+	}
+	return entries, nil
+}
+
+// copyKey identifies a copied entry by its food, portion, meal and time of day
+func copyKey(history *models.UserFoodHistory) string {
+	weight, meal := "", ""
+	if history.Weight != nil {
+		weight = strconv.FormatFloat(*history.Weight, 'f', -1, 64)
+	}
+	if history.Meal != nil {
+		meal = string(*history.Meal)
+	}
+	return fmt.Sprintf("%d|%d|%s|%s|%s", history.FoodID, history.Unit, weight, meal, history.CreatedAt.In(time.Local).Format("15:04:05.000000"))
+}
+
+// parseMeal validates an optional meal, an empty meal is nil
+func parseMeal(value string) (*models.MealType, error) {
+	if value == "" {
+		return nil, nil
+	}
+	meal := models.MealType(value)
+	switch meal {
+	case models.Breakfast, models.Lunch, models.Dinner, models.Snack:
+		return &meal, nil
+	default:
+		return nil, apperrors.ErrInvalidMeal()
+	}
+}
+
+// getFoods returns the foods visible to the user by ID
+func (s *foodLogService) getFoods(ids []uint, userID string) (map[uint]models.FoodWithNutritions, error) {
+	foods, err := s.foodLogRepo.GetFoodsByIDs(ids, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	foodByID := make(map[uint]models.FoodWithNutritions, len(foods))
+	for _, food := range foods {
+		if _, found := foodByID[food.Food.ID]; !found {
+			foodByID[food.Food.ID] = food
+		}
+	}
+	return foodByID, nil
+}
+
+// portionNutrition is the nutrition of a logged portion, a weight set by the user wins over the unit count
+func portionNutrition(unit int, weight *float64, nutrition *models.FoodNutrition) models.FoodNutrition {
+	if weight != nil && *weight > 0 {
+		return helper.CalculateNutrients(*weight, nutrition)
+	}
+	return helper.CalculateNutrients(helper.NutritionWeight(nutrition)*float64(unit), nutrition)
+}
+
+func toFoodLogEntry(history *models.UserFoodHistory, food *models.FoodWithNutritions) *dto.FoodLogEntry {
+	nutrition := portionNutrition(history.Unit, history.Weight, &food.Nutrition)
+	entry := &dto.FoodLogEntry{
+		ID:            history.ID,
+		FoodID:        history.FoodID,
+		Name:          food.Food.Name,
+		Unit:          history.Unit,
+		Weight:        round2(nutrition.Weight),
+		Calories:      round2(nutrition.Calories),
+		Protein:       round2(nutrition.Proteins),
+		Sugar:         round2(nutrition.Sugar),
+		Carbohydrates: round2(nutrition.Carbohydrates),
+		Fat:           round2(nutrition.Fat),
+		CreatedAt:     history.CreatedAt,
+	}
+	if history.Meal != nil {
+		entry.Meal = string(*history.Meal)
+	}
+	return entry
+}
@@ -0,0 +1,358 @@
+package services
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+	apperrors "github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+)
+
+// memFoodLogRepo keeps the food history and favorites in memory
+type memFoodLogRepo struct {
+	foods     map[uint]models.FoodWithNutritions
+	histories []models.UserFoodHistory
+	favorites []models.FavoriteFood
+}
+
+// newFoodLogTest returns a food log with a catalog rice of 130 kcal per 100 g, a catalog egg of
+// 70 kcal per 50 g egg and a private custom food of another user
+func newFoodLogTest() (FoodLogService, *memFoodLogRepo) {
+	other := foodViewer
+	repo := &memFoodLogRepo{foods: map[uint]models.FoodWithNutritions{
+		1: {Food: models.Food{ID: 1, Name: "Rice", Visibility: models.FoodVisibilityPublic}, Nutrition: models.FoodNutrition{Calories: 130, Weight: 100}},
+		2: {Food: models.Food{ID: 2, Name: "Egg", Visibility: models.FoodVisibilityPublic}, Nutrition: models.FoodNutrition{Calories: 70, Weight: 50}},
+		3: {Food: models.Food{ID: 3, Name: "Secret Sauce", OwnerID: &other, Visibility: models.FoodVisibilityPrivate}, Nutrition: models.FoodNutrition{Calories: 80, Weight: 100}},
+	}}
+	return NewFoodLogService(repo), repo
+}
+
+// log adds a history entry of the owner
+func (r *memFoodLogRepo) log(foodID uint, unit int, weight *float64, meal *models.MealType, at time.Time) {
+	r.histories = append(r.histories, models.UserFoodHistory{
+		ID:        uint(len(r.histories) + 1),
+		UserID:    foodOwner,
+		FoodID:    foodID,
+		Unit:      unit,
+		Weight:    weight,
+		Meal:      meal,
+		CreatedAt: at,
+	})
+}
+
+func (r *memFoodLogRepo) GetFoodLogStats(userID string, since time.Time, limit int) ([]models.FoodLogStat, error) {
+	statByFood := make(map[uint]*models.FoodLogStat)
+	for _, history := range r.histories {
+		if history.UserID != userID || history.CreatedAt.Before(since) {
+			continue
+		}
+		stat, found := statByFood[history.FoodID]
+		if !found {
+			stat = &models.FoodLogStat{FoodID: history.FoodID}
+			statByFood[history.FoodID] = stat
+		}
+		stat.Count++
+		if history.CreatedAt.After(stat.LastLoggedAt) {
+			stat.LastLoggedAt = history.CreatedAt
+		}
+	}
+
+	stats := make([]models.FoodLogStat, 0, len(statByFood))
+	for _, stat := range statByFood {
+		stats = append(stats, *stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Count != stats[j].Count {
+			return stats[i].Count > stats[j].Count
+		}
+		return stats[i].LastLoggedAt.After(stats[j].LastLoggedAt)
+	})
+	if len(stats) > limit {
+		stats = stats[:limit]
+	}
+	return stats, nil
+}
+
+func (r *memFoodLogRepo) GetLastPortions(userID string, foodIDs []uint) (map[uint]models.UserFoodHistory, error) {
+	portions := make(map[uint]models.UserFoodHistory)
+	for _, history := range r.histories {
+		last, found := portions[history.FoodID]
+		if history.UserID == userID && (!found || history.CreatedAt.After(last.CreatedAt)) {
+			portions[history.FoodID] = history
+		}
+	}
+	return portions, nil
+}
+
+func (r *memFoodLogRepo) GetFoodsByIDs(ids []uint, userID string) ([]models.FoodWithNutritions, error) {
+	var foods []models.FoodWithNutritions
+	for _, id := range ids {
+		food, found := r.foods[id]
+		if found && (food.Food.Visibility == models.FoodVisibilityPublic || (food.Food.OwnerID != nil && *food.Food.OwnerID == userID)) {
+			foods = append(foods, food)
+		}
+	}
+	return foods, nil
+}
+
+func (r *memFoodLogRepo) GetHistoriesBetween(userID string, start, end time.Time, meal *models.MealType) ([]models.UserFoodHistory, error) {
+	var histories []models.UserFoodHistory
+	for _, history := range r.histories {
+		if history.UserID != userID || history.CreatedAt.Before(start) || !history.CreatedAt.Before(end) {
+			continue
+		}
+		if meal != nil && (history.Meal == nil || *history.Meal != *meal) {
+			continue
+		}
+		histories = append(histories, history)
+	}
+	return histories, nil
+}
+
+func (r *memFoodLogRepo) CreateHistories(histories []models.UserFoodHistory) error {
+	for i := range histories {
+		histories[i].ID = uint(len(r.histories) + 1)
+		if histories[i].CreatedAt.IsZero() {
+			histories[i].CreatedAt = time.Now()
+		}
+		r.histories = append(r.histories, histories[i])
+	}
+	return nil
+}
+
+func (r *memFoodLogRepo) GetFavorites(userID string) ([]models.FavoriteFood, error) {
+	var favorites []models.FavoriteFood
+	for _, favorite := range r.favorites {
+		if favorite.UserID == userID {
+			favorites = append(favorites, favorite)
+		}
+	}
+	return favorites, nil
+}
+
+func (r *memFoodLogRepo) AddFavorite(userID string, foodID uint) error {
+	r.favorites = append(r.favorites, models.FavoriteFood{UserID: userID, FoodID: foodID})
+	return nil
+}
+
+func (r *memFoodLogRepo) RemoveFavorite(userID string, foodID uint) error {
+	favorites := r.favorites[:0]
+	for _, favorite := range r.favorites {
+		if favorite.UserID != userID || favorite.FoodID != foodID {
+			favorites = append(favorites, favorite)
+		}
+	}
+	r.favorites = favorites
+	return nil
+}
+
+func float64Ptr(value float64) *float64 {
+	return &value
+}
+
+func mealPtr(meal models.MealType) *models.MealType {
+	return &meal
+}
+
+func TestQuickLogReusesLastPortion(t *testing.T) {
+	service, repo := newFoodLogTest()
+	now := time.Now()
+	repo.log(1, 1, float64Ptr(150), nil, now.Add(-48*time.Hour))
+	repo.log(1, 1, float64Ptr(200), nil, now.Add(-24*time.Hour))
+	repo.log(2, 2, nil, nil, now.Add(-24*time.Hour))
+
+	tests := []struct {
+		name         string
+		req          dto.QuickLogRequest
+		wantUnit     int
+		wantWeight   float64
+		wantCalories float64
+	}{
+		{"last weight", dto.QuickLogRequest{FoodID: 1}, 1, 200, 260},
+		{"last unit", dto.QuickLogRequest{FoodID: 2}, 2, 100, 140},
+		{"requested weight", dto.QuickLogRequest{FoodID: 1, Weight: float64Ptr(50)}, 0, 50, 65},
+		{"requested unit", dto.QuickLogRequest{FoodID: 2, Unit: 3}, 3, 150, 210},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := service.QuickLog(foodOwner, &tt.req)
+			if err != nil {
+				t.Fatalf("QuickLog() error = %v", err)
+			}
+			if entry.Weight != tt.wantWeight || entry.Calories != tt.wantCalories {
+				t.Errorf("QuickLog() = %+v, want %v g with %v kcal", entry, tt.wantWeight, tt.wantCalories)
+			}
+			if tt.wantUnit > 0 && entry.Unit != tt.wantUnit {
+				t.Errorf("Unit = %d, want %d", entry.Unit, tt.wantUnit)
+			}
+		})
+	}
+}
+
+func TestQuickLogNeverLoggedFood(t *testing.T) {
+	service, repo := newFoodLogTest()
+
+	entry, err := service.QuickLog(foodOwner, &dto.QuickLogRequest{FoodID: 2, Meal: "breakfast"})
+	if err != nil {
+		t.Fatalf("QuickLog() error = %v", err)
+	}
+	if entry.Unit != 1 || entry.Weight != 50 || entry.Meal != "breakfast" {
+		t.Errorf("QuickLog() = %+v, want one 50 g unit for breakfast", entry)
+	}
+	if len(repo.histories) != 1 || repo.histories[0].UserID != foodOwner {
+		t.Errorf("histories = %+v, want one entry of the user", repo.histories)
+	}
+}
+
+func TestQuickLogRejects(t *testing.T) {
+	service, repo := newFoodLogTest()
+
+	tests := []struct {
+		name    string
+		req     dto.QuickLogRequest
+		wantErr error
+	}{
+		{"unknown food", dto.QuickLogRequest{FoodID: 99}, apperrors.ErrFoodNotFound()},
+		{"private food of another user", dto.QuickLogRequest{FoodID: 3}, apperrors.ErrFoodNotFound()},
+		{"unknown meal", dto.QuickLogRequest{FoodID: 1, Meal: "brunch"}, apperrors.ErrInvalidMeal()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.QuickLog(foodOwner, &tt.req)
+			if err == nil || err.Error() != tt.wantErr.Error() {
+				t.Errorf("QuickLog() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+	if len(repo.histories) != 0 {
+		t.Errorf("histories = %+v, want nothing logged", repo.histories)
+	}
+}
+
+func TestGetFrequentFoods(t *testing.T) {
+	service, repo := newFoodLogTest()
+	now := time.Now()
+	repo.log(1, 1, nil, nil, now.Add(-3*time.Hour))
+	repo.log(1, 1, nil, nil, now.Add(-2*time.Hour))
+	repo.log(2, 1, nil, nil, now.Add(-1*time.Hour))
+	// too long ago to count
+	repo.log(2, 1, nil, nil, now.Add(-frequentFoodsWindow-time.Hour))
+	repo.log(2, 1, nil, nil, now.Add(-frequentFoodsWindow-2*time.Hour))
+
+	if err := service.AddFavorite(foodOwner, 2); err != nil {
+		t.Fatalf("AddFavorite() error = %v", err)
+	}
+	if err := service.AddFavorite(foodOwner, 3); err == nil || err.Error() != apperrors.ErrFoodNotFound().Error() {
+		t.Errorf("AddFavorite() of a private food of another user error = %v, want %v", err, apperrors.ErrFoodNotFound())
+	}
+
+	foods, err := service.GetFrequentFoods(foodOwner, 0)
+	if err != nil {
+		t.Fatalf("GetFrequentFoods() error = %v", err)
+	}
+	if len(foods) != 2 {
+		t.Fatalf("GetFrequentFoods() = %+v, want 2 foods", foods)
+	}
+	// pinned foods come first
+	if foods[0].FoodID != 2 || !foods[0].Pinned || foods[0].Count != 1 {
+		t.Errorf("foods[0] = %+v, want the pinned egg logged once", foods[0])
+	}
+	if foods[1].FoodID != 1 || foods[1].Pinned || foods[1].Count != 2 || foods[1].Calories != 130 {
+		t.Errorf("foods[1] = %+v, want rice logged twice", foods[1])
+	}
+
+	if err := service.RemoveFavorite(foodOwner, 2); err != nil {
+		t.Fatalf("RemoveFavorite() error = %v", err)
+	}
+	foods, err = service.GetFrequentFoods(foodOwner, 1)
+	if err != nil {
+		t.Fatalf("GetFrequentFoods() error = %v", err)
+	}
+	if len(foods) != 1 || foods[0].FoodID != 1 {
+		t.Errorf("GetFrequentFoods() = %+v, want only the most frequent food", foods)
+	}
+}
+
+// day returns the time of day on a date in the local time zone
+func day(date string, hour, minute int) time.Time {
+	d, _ := time.ParseInLocation("2006-01-02", date, time.Local)
+	return d.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+func TestCopyFoodLog(t *testing.T) {
+	service, repo := newFoodLogTest()
+	repo.log(1, 1, float64Ptr(180), mealPtr(models.Breakfast), day("2026-10-01", 7, 30))
+	repo.log(2, 2, nil, mealPtr(models.Breakfast), day("2026-10-01", 7, 35))
+	repo.log(1, 1, nil, mealPtr(models.Dinner), day("2026-10-01", 19, 0))
+	repo.log(3, 1, nil, mealPtr(models.Dinner), day("2026-10-01", 19, 5)) // no longer visible
+	repo.log(2, 1, nil, mealPtr(models.Breakfast), day("2026-10-02", 8, 0))
+
+	entries, err := service.CopyFoodLog(foodOwner, &dto.CopyFoodLogRequest{From: "2026-10-01", To: "2026-10-05"})
+	if err != nil {
+		t.Fatalf("CopyFoodLog() error = %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("CopyFoodLog() = %+v, want the 3 visible entries", entries)
+	}
+	if want := day("2026-10-05", 7, 30); !entries[0].CreatedAt.Equal(want) || entries[0].Weight != 180 || entries[0].Meal != "breakfast" {
+		t.Errorf("entries[0] = %+v, want 180 g for breakfast at %s", entries[0], want)
+	}
+
+	// copying again adds nothing
+	again, err := service.CopyFoodLog(foodOwner, &dto.CopyFoodLogRequest{From: "2026-10-01", To: "2026-10-05"})
+	if err != nil {
+		t.Fatalf("CopyFoodLog() again error = %v", err)
+	}
+	if len(again) != 0 || len(repo.histories) != 8 {
+		t.Errorf("copying again added %d entries, histories = %d, want none added", len(again), len(repo.histories))
+	}
+}
+
+func TestCopyFoodLogMeal(t *testing.T) {
+	service, repo := newFoodLogTest()
+	repo.log(1, 1, nil, mealPtr(models.Breakfast), day("2026-10-01", 7, 30))
+	repo.log(2, 1, nil, mealPtr(models.Dinner), day("2026-10-01", 19, 0))
+	repo.log(2, 1, nil, nil, day("2026-10-01", 20, 0))
+
+	entries, err := service.CopyFoodLog(foodOwner, &dto.CopyFoodLogRequest{From: "2026-10-01", To: "2026-10-03", Meal: "dinner"})
+	if err != nil {
+		t.Fatalf("CopyFoodLog() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].FoodID != 2 || entries[0].Meal != "dinner" {
+		t.Errorf("CopyFoodLog() = %+v, want the dinner only", entries)
+	}
+}
+
+func TestCopyFoodLogRejects(t *testing.T) {
+	service, repo := newFoodLogTest()
+	repo.log(1, 1, nil, nil, day("2026-10-01", 7, 30))
+	repo.log(3, 1, nil, nil, day("2026-10-02", 7, 30))
+
+	tests := []struct {
+		name    string
+		req     dto.CopyFoodLogRequest
+		wantErr error
+	}{
+		{"same date", dto.CopyFoodLogRequest{From: "2026-10-01", To: "2026-10-01"}, apperrors.ErrCopySameDate()},
+		{"invalid date", dto.CopyFoodLogRequest{From: "01-10-2026"}, apperrors.ErrInvalidDate()},
+		{"invalid meal", dto.CopyFoodLogRequest{From: "2026-10-01", To: "2026-10-05", Meal: "brunch"}, apperrors.ErrInvalidMeal()},
+		{"empty date", dto.CopyFoodLogRequest{From: "2026-09-01", To: "2026-10-05"}, apperrors.ErrNoFoodEntries()},
+		{"only foods no longer visible", dto.CopyFoodLogRequest{From: "2026-10-02", To: "2026-10-05"}, apperrors.ErrNoFoodEntries()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.CopyFoodLog(foodOwner, &tt.req)
+			if err == nil || err.Error() != tt.wantErr.Error() {
+				t.Errorf("CopyFoodLog() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+	if len(repo.histories) != 2 {
+		t.Errorf("histories = %d, want nothing copied", len(repo.histories))
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+	apperrors "github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	helper "github.com/rizkirmdhnnn/sweetlife-backend-go/helpers"
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/repositories"
 )
@@ -52,6 +54,13 @@ func (h *healthProfileService) GetHealthProfile(userID string) (*dto.HealthProfi
 		ActivityLevel:   healthProfile.ActivityLevel,
 	}
 
+	// get dietary preferences
+	preferences, err := h.healthRepo.GetDietaryPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+	resp.DietaryPreferences = preferences
+
 	// if user is diabetic, get diabetes details
 	if healthProfile.IsDiabetic {
 		diabetesDetails, err := h.healthRepo.GetDiabetesDetailsByProfileID(fmt.Sprintf("%d", healthProfile.ID))
@@ -94,6 +103,10 @@ func (h *healthProfileService) CreateHealthProfile(profile *dto.HealthProfileDto
 		return errors.New("height and weight must be greater than zero")
 	}
 
+	if err := validateDietaryPreferences(profile.DietaryPreferences); err != nil {
+		return err
+	}
+
 	//find user by id
 	userData, err := h.authRepo.GetUserById(profile.UserID)
 	if err != nil {
@@ -117,6 +130,13 @@ func (h *healthProfileService) CreateHealthProfile(profile *dto.HealthProfileDto
 		return err
 	}
 
+	// save dietary preferences
+	if len(profile.DietaryPreferences) > 0 {
+		if err := h.healthRepo.ReplaceDietaryPreferences(profile.UserID, profile.DietaryPreferences); err != nil {
+			return err
+		}
+	}
+
 	// create diabetes details if user is diabetic
 	if profile.IsDiabetic {
 		diabetesData := models.DiabetesDetails{
@@ -170,6 +190,10 @@ func (h *healthProfileService) CreateHealthProfile(profile *dto.HealthProfileDto
 
 // UpdateHealthProfile implements HealthProfileService.
 func (h *healthProfileService) UpdateHealthProfile(req *dto.HealthProfileDto) error {
+	if err := validateDietaryPreferences(req.DietaryPreferences); err != nil {
+		return err
+	}
+
 	// 1. Find user by ID
 	userData, err := h.authRepo.GetUserById(req.UserID)
 	if err != nil {
@@ -226,6 +250,13 @@ func (h *healthProfileService) UpdateHealthProfile(req *dto.HealthProfileDto) er
 		return fmt.Errorf("failed to update health profile: %w", err)
 	}
 
+	// 6. Replace dietary preferences when they are sent
+	if req.DietaryPreferences != nil {
+		if err := h.healthRepo.ReplaceDietaryPreferences(req.UserID, req.DietaryPreferences); err != nil {
+			return fmt.Errorf("failed to update dietary preferences: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -314,3 +345,13 @@ func (h *healthProfileService) updateRiskAssessment(userData *models.User, healt
 	// Save risk assessment
 	return h.healthRepo.UpdateAssessment(risk)
 }
+
+// validateDietaryPreferences checks that all dietary preferences are supported
+func validateDietaryPreferences(preferences []models.DietaryPreference) error {
+	for _, preference := range preferences {
+		if !helper.IsValidDietaryPreference(preference) {
+			return fmt.Errorf("%w: %s", apperrors.ErrInvalidDietaryPreference(), preference)
+		}
+	}
+	return nil
+}
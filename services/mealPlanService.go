@@ -0,0 +1,586 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+	apperrors "github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	helper "github.com/rizkirmdhnnn/sweetlife-backend-go/helpers"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/repositories"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxLocalCandidates limits how many local foods are considered for a plan
+	maxLocalCandidates = 200
+	// minPortion and maxPortion bound the portion multiplier of a planned food
+	minPortion = 0.5
+	maxPortion = 2.0
+	// candidates scoring within this margin of the best one are equally good,
+	// picking between them gives some variety between days
+	candidateScoreMargin = 0.1
+)
+
+// mealShares is the part of the daily targets given to each meal
+var mealShares = []struct {
+	Meal  models.MealType
+	Share float64
+}{
+	{models.Breakfast, 0.25},
+	{models.Lunch, 0.35},
+	{models.Dinner, 0.30},
+	{models.Snack, 0.10},
+}
+
+type MealPlanService interface {
+	GetMealPlan(userID string, date time.Time) (*dto.MealPlanResponse, error)
+	SwapItem(userID string, itemID uint, req *dto.SwapMealPlanItemRequest) (*dto.MealPlanResponse, error)
+}
+
+type mealPlanService struct {
+	mealPlanRepo      repositories.MealPlanRepository
+	recomendationRepo repositories.RecomendationRepo
+	healthRepo        repositories.HealthProfileRepository
+	authRepo          repositories.AuthRepository
+	foodTagRepo       repositories.FoodTagRepository
+	feedbackRepo      repositories.FeedbackRepository
+}
+
+func NewMealPlanService(mealPlanRepo repositories.MealPlanRepository, recomendationRepo repositories.RecomendationRepo, healthRepo repositories.HealthProfileRepository, authRepo repositories.AuthRepository, foodTagRepo repositories.FoodTagRepository, feedbackRepo repositories.FeedbackRepository) MealPlanService {
+	if mealPlanRepo == nil {
+		panic("mealPlanRepo cannot be nil")
+	}
+	if recomendationRepo == nil {
+		panic("recomendationRepo cannot be nil")
+	}
+	if foodTagRepo == nil {
+		panic("foodTagRepo cannot be nil")
+	}
+	if feedbackRepo == nil {
+		panic("feedbackRepo cannot be nil")
+	}
+	return &mealPlanService{
+		mealPlanRepo:      mealPlanRepo,
+		recomendationRepo: recomendationRepo,
+		healthRepo:        healthRepo,
+		authRepo:          authRepo,
+		foodTagRepo:       foodTagRepo,
+		feedbackRepo:      feedbackRepo,
+	}
+}
+
+// mealCandidate is a food that can be planned, with the nutrition of one portion
+type mealCandidate struct {
+	FoodID         *uint
+	Name           string
+	Image          string
+	Weight         float64
+	Calories       float64
+	Sugar          float64
+	Carbohydrates  float64
+	Fat            float64
+	Proteins       float64
+	Recommendation bool
+	// DietaryScore counts the dietary preferences the food is confirmed to fit
+	DietaryScore int
+	Liked        bool
+}
+
+// mealTargets is the nutrition budget of a day or a meal
+type mealTargets struct {
+	Calories      float64
+	Sugar         float64
+	Carbohydrates float64
+}
+
+// GetMealPlan implements MealPlanService.
+// The plan of a day is generated once and kept, so it does not change on every request.
+func (s *mealPlanService) GetMealPlan(userID string, date time.Time) (*dto.MealPlanResponse, error) {
+	plan, err := s.mealPlanRepo.GetPlanByDate(userID, date)
+	if err == nil {
+		return toMealPlanResponse(plan), nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	targets, err := s.getDailyTargets(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := s.getCandidates(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan = &models.MealPlan{
+		UserID:         userID,
+		Date:           date,
+		TargetCalories: targets.Calories,
+		TargetSugar:    targets.Sugar,
+		TargetCarbs:    targets.Carbohydrates,
+	}
+
+	// pick one food per meal, never twice in the same day
+	used := make(map[string]bool)
+	for _, meal := range mealShares {
+		item := pickMealItem(candidates, scaleTargets(targets, meal.Share), used, date.YearDay())
+		if item == nil {
+			return nil, apperrors.ErrNoMealCandidate()
+		}
+		item.Meal = meal.Meal
+		used[strings.ToLower(item.Name)] = true
+		plan.Items = append(plan.Items, *item)
+	}
+	fitPortions(plan.Items, targets)
+
+	if err := s.mealPlanRepo.CreatePlan(plan); err != nil {
+		// another request may have created the plan in the meantime
+		if existing, getErr := s.mealPlanRepo.GetPlanByDate(userID, date); getErr == nil {
+			return toMealPlanResponse(existing), nil
+		}
+		return nil, err
+	}
+
+	return toMealPlanResponse(plan), nil
+}
+
+// SwapItem implements MealPlanService.
+// The item is replaced by the requested food, or by the next best food not yet in the plan.
+func (s *mealPlanService) SwapItem(userID string, itemID uint, req *dto.SwapMealPlanItemRequest) (*dto.MealPlanResponse, error) {
+	item, err := s.mealPlanRepo.GetItemByID(itemID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrMealPlanItemNotFound()
+		}
+		return nil, err
+	}
+
+	plan, err := s.mealPlanRepo.GetPlanByID(item.PlanID)
+	if err != nil {
+		return nil, err
+	}
+	if plan.UserID != userID {
+		return nil, apperrors.ErrMealPlanItemNotFound()
+	}
+
+	targets := scaleTargets(mealTargets{
+		Calories:      plan.TargetCalories,
+		Sugar:         plan.TargetSugar,
+		Carbohydrates: plan.TargetCarbs,
+	}, mealShare(item.Meal))
+
+	var replacement *models.MealPlanItem
+	if req.FoodID != nil {
+		food, err := s.mealPlanRepo.GetFoodByID(*req.FoodID, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, apperrors.ErrFoodNotFound()
+			}
+			return nil, err
+		}
+		replacement = portionItem(localCandidate(food, ""), targets.Calories)
+	} else {
+		candidates, err := s.getCandidates(userID)
+		if err != nil {
+			return nil, err
+		}
+
+		used := make(map[string]bool)
+		for _, planned := range plan.Items {
+			used[strings.ToLower(planned.Name)] = true
+		}
+		replacement = pickMealItem(candidates, targets, used, int(item.ID))
+		if replacement == nil {
+			return nil, apperrors.ErrNoMealCandidate()
+		}
+	}
+
+	replacement.ID = item.ID
+	replacement.PlanID = item.PlanID
+	replacement.Meal = item.Meal
+	replacement.CreatedAt = item.CreatedAt
+	if err := s.mealPlanRepo.UpdateItem(replacement); err != nil {
+		return nil, err
+	}
+
+	for i := range plan.Items {
+		if plan.Items[i].ID == replacement.ID {
+			plan.Items[i] = *replacement
+		}
+	}
+
+	return toMealPlanResponse(plan), nil
+}
+
+// getDailyTargets calculates the daily calories, sugar and carbohydrate targets of a user
+func (s *mealPlanService) getDailyTargets(userID string) (mealTargets, error) {
+	user, err := s.authRepo.GetUserById(userID)
+	if err != nil {
+		return mealTargets{}, err
+	}
+
+	profile, err := s.healthRepo.GetHealthProfileByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return mealTargets{}, apperrors.ErrHealthProfileRequired()
+		}
+		return mealTargets{}, err
+	}
+
+	dailyCalories, err := helper.CalculateDailyCalories(dto.DailyCaloriesRequest{
+		Height:        profile.Height,
+		Weight:        profile.Weight,
+		Gender:        user.Gender,
+		Age:           user.Age,
+		ActivityLevel: profile.ActivityLevel,
+	})
+	if err != nil {
+		return mealTargets{}, err
+	}
+
+	return mealTargets{
+		Calories:      dailyCalories,
+		Sugar:         helper.CalculateDailySugar(dailyCalories, profile.IsDiabetic),
+		Carbohydrates: helper.CalculateDialyCarbs(dailyCalories),
+	}, nil
+}
+
+// getCandidates merges the ML food recommendations with the local food table.
+// Recommendations use the local nutrition, the ML service does not return sugar so
+// recommendations missing from the local table are left out.
+// Foods that do not fit the dietary preferences of the user or were disliked are left out.
+func (s *mealPlanService) getCandidates(userID string) ([]mealCandidate, error) {
+	var candidates []mealCandidate
+	seen := make(map[string]bool)
+
+	riskScore, err := getRiskScore(s.healthRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the plan can still be built from local foods when the ML service is down
+	recommendations, err := s.recomendationRepo.GetFoodRecomendations(riskScore)
+	if err != nil {
+		log.Printf("meal plan: failed to get food recommendations: %v", err)
+	} else {
+		var names []string
+		for _, group := range recommendations.FoodRecomendation {
+			for _, food := range group {
+				names = append(names, food.Name)
+			}
+		}
+
+		localFoods, err := s.mealPlanRepo.GetFoodsByNames(names)
+		if err != nil {
+			return nil, err
+		}
+		localByName := make(map[string]*models.FoodWithNutritions)
+		for i := range localFoods {
+			if _, found := localByName[strings.ToLower(localFoods[i].Food.Name)]; !found {
+				localByName[strings.ToLower(localFoods[i].Food.Name)] = &localFoods[i]
+			}
+		}
+
+		for _, group := range recommendations.FoodRecomendation {
+			for _, food := range group {
+				key := strings.ToLower(food.Name)
+				local, found := localByName[key]
+				if seen[key] || !found {
+					continue
+				}
+				seen[key] = true
+
+				candidate := localCandidate(local, food.Image)
+				candidate.Recommendation = true
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+
+	localFoods, err := s.mealPlanRepo.GetFoodCandidates(maxLocalCandidates)
+	if err != nil {
+		return nil, err
+	}
+	for i := range localFoods {
+		key := strings.ToLower(localFoods[i].Food.Name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		candidates = append(candidates, localCandidate(&localFoods[i], ""))
+	}
+
+	names := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		names = append(names, candidate.Name)
+	}
+	diet, err := newDietaryFilter(s.healthRepo, s.foodTagRepo, userID, names)
+	if err != nil {
+		return nil, err
+	}
+
+	feedback, err := s.feedbackRepo.GetFeedbackByUser(userID, models.FoodFeedback)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := candidates[:0]
+	for _, candidate := range candidates {
+		if !diet.allows(candidate.Name) || feedback[strings.ToLower(candidate.Name)] == models.DislikeRating {
+			continue
+		}
+		candidate.Liked = feedback[strings.ToLower(candidate.Name)] == models.LikeRating
+		candidate.DietaryScore = diet.score(candidate.Name)
+		allowed = append(allowed, candidate)
+	}
+
+	return allowed, nil
+}
+
+// localCandidate creates a candidate from a local food, one portion is the nutrition weight
+func localCandidate(food *models.FoodWithNutritions, image string) mealCandidate {
+	foodID := food.Food.ID
+	return mealCandidate{
+		FoodID:        &foodID,
+		Name:          food.Food.Name,
+		Image:         image,
+		Weight:        food.Nutrition.Weight,
+		Calories:      food.Nutrition.Calories,
+		Sugar:         food.Nutrition.Sugar,
+		Carbohydrates: food.Nutrition.Carbohydrates,
+		Fat:           food.Nutrition.Fat,
+		Proteins:      food.Nutrition.Proteins,
+	}
+}
+
+// pickMealItem returns the candidate that best fits the targets of a meal.
+// Calories should be close to the target, going over the sugar or carbohydrate budget is penalized.
+func pickMealItem(candidates []mealCandidate, targets mealTargets, exclude map[string]bool, seed int) *models.MealPlanItem {
+	type scored struct {
+		item  *models.MealPlanItem
+		score float64
+	}
+
+	var options []scored
+	best := math.Inf(1)
+	for _, candidate := range candidates {
+		if exclude[strings.ToLower(candidate.Name)] {
+			continue
+		}
+
+		item := portionItem(candidate, targets.Calories)
+		score := math.Abs(item.Calories-targets.Calories) / targets.Calories
+		if targets.Carbohydrates > 0 {
+			score += math.Max(0, item.Carbohydrates-targets.Carbohydrates) / targets.Carbohydrates
+		}
+		if targets.Sugar > 0 {
+			// sugar matters the most for diabetics, weigh it double
+			score += 2 * math.Max(0, item.Sugar-targets.Sugar) / targets.Sugar
+		}
+		if candidate.Recommendation {
+			score -= candidateScoreMargin / 2
+		}
+		score -= float64(candidate.DietaryScore) * candidateScoreMargin / 4
+		if candidate.Liked {
+			score -= candidateScoreMargin / 2
+		}
+
+		options = append(options, scored{item: item, score: score})
+		best = math.Min(best, score)
+	}
+
+	var good []*models.MealPlanItem
+	for _, option := range options {
+		if option.score <= best+candidateScoreMargin {
+			good = append(good, option.item)
+		}
+	}
+	if len(good) == 0 {
+		return nil
+	}
+	if seed < 0 {
+		seed = -seed
+	}
+	return good[seed%len(good)]
+}
+
+// portionItem creates a plan item of the candidate with the portion closest to the calorie target
+func portionItem(candidate mealCandidate, targetCalories float64) *models.MealPlanItem {
+	portion := 1.0
+	if candidate.Calories > 0 && targetCalories > 0 {
+		portion = roundPortion(targetCalories / candidate.Calories)
+	}
+
+	return &models.MealPlanItem{
+		FoodID:        candidate.FoodID,
+		Name:          candidate.Name,
+		Image:         candidate.Image,
+		Portion:       portion,
+		Weight:        round2(candidate.Weight * portion),
+		Calories:      round2(candidate.Calories * portion),
+		Sugar:         round2(candidate.Sugar * portion),
+		Carbohydrates: round2(candidate.Carbohydrates * portion),
+		Fat:           round2(candidate.Fat * portion),
+		Proteins:      round2(candidate.Proteins * portion),
+	}
+}
+
+// fitPortions scales all portions of a plan so the totals stay within the daily targets.
+// Portions are scaled down when a budget is exceeded and up when the plan has too few calories.
+// Portions stay between minPortion and maxPortion, so the plan can still miss the targets,
+// the response reports by how much.
+func fitPortions(items []models.MealPlanItem, targets mealTargets) {
+	totals := sumItems(items)
+	if totals.Calories <= 0 {
+		return
+	}
+
+	factor := 1.0
+	if totals.Calories > targets.Calories*1.05 {
+		factor = targets.Calories / totals.Calories
+	} else if totals.Calories < targets.Calories*0.9 {
+		factor = targets.Calories / totals.Calories
+	}
+	if totals.Sugar > 0 && targets.Sugar > 0 {
+		factor = math.Min(factor, targets.Sugar/totals.Sugar)
+	}
+	if totals.Carbohydrates > 0 && targets.Carbohydrates > 0 {
+		factor = math.Min(factor, targets.Carbohydrates/totals.Carbohydrates)
+	}
+	if factor == 1 {
+		return
+	}
+
+	for i := range items {
+		portion := roundPortion(items[i].Portion * factor)
+		if portion == items[i].Portion {
+			continue
+		}
+		ratio := portion / items[i].Portion
+		items[i].Portion = portion
+		items[i].Weight = round2(items[i].Weight * ratio)
+		items[i].Calories = round2(items[i].Calories * ratio)
+		items[i].Sugar = round2(items[i].Sugar * ratio)
+		items[i].Carbohydrates = round2(items[i].Carbohydrates * ratio)
+		items[i].Fat = round2(items[i].Fat * ratio)
+		items[i].Proteins = round2(items[i].Proteins * ratio)
+	}
+}
+
+// toMealPlanResponse groups the items of a plan by meal
+func toMealPlanResponse(plan *models.MealPlan) *dto.MealPlanResponse {
+	totals := sumItems(plan.Items)
+	resp := &dto.MealPlanResponse{
+		ID:   plan.ID,
+		Date: plan.Date.Format("2006-01-02"),
+		Targets: dto.MealPlanNutrition{
+			Calories:      plan.TargetCalories,
+			Sugar:         plan.TargetSugar,
+			Carbohydrates: plan.TargetCarbs,
+		},
+		Totals: dto.MealPlanNutrition{
+			Calories:      round2(totals.Calories),
+			Sugar:         round2(totals.Sugar),
+			Carbohydrates: round2(totals.Carbohydrates),
+		},
+		Difference: dto.MealPlanNutrition{
+			Calories:      round2(totals.Calories - plan.TargetCalories),
+			Sugar:         round2(totals.Sugar - plan.TargetSugar),
+			Carbohydrates: round2(totals.Carbohydrates - plan.TargetCarbs),
+		},
+		WithinTargets: withinTargets(totals, mealTargets{
+			Calories:      plan.TargetCalories,
+			Sugar:         plan.TargetSugar,
+			Carbohydrates: plan.TargetCarbs,
+		}),
+		Meals: []dto.MealPlanMeal{},
+	}
+
+	for _, meal := range mealShares {
+		mealResp := dto.MealPlanMeal{
+			Meal:           string(meal.Meal),
+			TargetCalories: round2(plan.TargetCalories * meal.Share),
+			Items:          []dto.MealPlanItem{},
+		}
+		for _, item := range plan.Items {
+			if item.Meal != meal.Meal {
+				continue
+			}
+			mealResp.Items = append(mealResp.Items, dto.MealPlanItem{
+				ID:            item.ID,
+				FoodID:        item.FoodID,
+				Name:          item.Name,
+				Image:         item.Image,
+				Portion:       item.Portion,
+				Weight:        item.Weight,
+				Calories:      item.Calories,
+				Sugar:         item.Sugar,
+				Carbohydrates: item.Carbohydrates,
+				Fat:           item.Fat,
+				Proteins:      item.Proteins,
+			})
+		}
+		resp.Meals = append(resp.Meals, mealResp)
+	}
+
+	return resp
+}
+
+// withinTargets reports whether the totals are within the range fitPortions aims for:
+// calories between 90% and 105% of the target, sugar and carbohydrates not over budget
+func withinTargets(totals, targets mealTargets) bool {
+	if totals.Calories < targets.Calories*0.9 || totals.Calories > targets.Calories*1.05 {
+		return false
+	}
+	if targets.Sugar > 0 && totals.Sugar > targets.Sugar {
+		return false
+	}
+	if targets.Carbohydrates > 0 && totals.Carbohydrates > targets.Carbohydrates {
+		return false
+	}
+	return true
+}
+
+func sumItems(items []models.MealPlanItem) mealTargets {
+	var totals mealTargets
+	for _, item := range items {
+		totals.Calories += item.Calories
+		totals.Sugar += item.Sugar
+		totals.Carbohydrates += item.Carbohydrates
+	}
+	return totals
+}
+
+func scaleTargets(targets mealTargets, share float64) mealTargets {
+	return mealTargets{
+		Calories:      targets.Calories * share,
+		Sugar:         targets.Sugar * share,
+		Carbohydrates: targets.Carbohydrates * share,
+	}
+}
+
+func mealShare(meal models.MealType) float64 {
+	for _, m := range mealShares {
+		if m.Meal == meal {
+			return m.Share
+		}
+	}
+	return 0
+}
+
+// roundPortion rounds a portion to a quarter and keeps it within the allowed range
+func roundPortion(portion float64) float64 {
+	portion = math.Round(portion*4) / 4
+	return math.Min(maxPortion, math.Max(minPortion, portion))
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+)
+
+func TestPortionItem(t *testing.T) {
+	candidate := mealCandidate{Name: "Nasi", Weight: 100, Calories: 200, Sugar: 1, Carbohydrates: 40, Fat: 2, Proteins: 4}
+
+	tests := []struct {
+		name           string
+		targetCalories float64
+		wantPortion    float64
+	}{
+		{"exact portion", 300, 1.5},
+		{"rounded to a quarter", 260, 1.25},
+		{"at least minPortion", 20, minPortion},
+		{"at most maxPortion", 2000, maxPortion},
+		{"no target", 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := portionItem(candidate, tt.targetCalories)
+			if item.Portion != tt.wantPortion {
+				t.Fatalf("Portion = %v, want %v", item.Portion, tt.wantPortion)
+			}
+			if item.Weight != 100*tt.wantPortion || item.Calories != 200*tt.wantPortion || item.Carbohydrates != 40*tt.wantPortion {
+				t.Errorf("item %+v is not scaled by the portion", item)
+			}
+		})
+	}
+}
+
+func TestPortionItemWithoutCalories(t *testing.T) {
+	item := portionItem(mealCandidate{Name: "Air", Weight: 200}, 500)
+	if item.Portion != 1 || item.Weight != 200 {
+		t.Errorf("portionItem() = %+v, want one portion of 200 g", item)
+	}
+}
+
+func TestFitPortions(t *testing.T) {
+	newItems := func() []models.MealPlanItem {
+		return []models.MealPlanItem{
+			{Portion: 1, Weight: 100, Calories: 400, Sugar: 5, Carbohydrates: 50},
+			{Portion: 1, Weight: 150, Calories: 600, Sugar: 10, Carbohydrates: 70},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		targets      mealTargets
+		wantPortions []float64
+	}{
+		{"within targets", mealTargets{Calories: 1000, Sugar: 20, Carbohydrates: 150}, []float64{1, 1}},
+		{"too many calories", mealTargets{Calories: 500, Sugar: 20, Carbohydrates: 150}, []float64{0.5, 0.5}},
+		{"too few calories", mealTargets{Calories: 1500, Sugar: 30, Carbohydrates: 200}, []float64{1.5, 1.5}},
+		{"sugar over budget", mealTargets{Calories: 1000, Sugar: 11.25, Carbohydrates: 150}, []float64{0.75, 0.75}},
+		{"carbohydrates over budget", mealTargets{Calories: 1000, Sugar: 20, Carbohydrates: 90}, []float64{0.75, 0.75}},
+		{"portions stay bounded", mealTargets{Calories: 100, Sugar: 20, Carbohydrates: 150}, []float64{minPortion, minPortion}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := newItems()
+			fitPortions(items, tt.targets)
+
+			for i, item := range items {
+				if item.Portion != tt.wantPortions[i] {
+					t.Errorf("items[%d].Portion = %v, want %v", i, item.Portion, tt.wantPortions[i])
+				}
+			}
+
+			// nutrition follows the portion
+			original := newItems()
+			for i, item := range items {
+				if item.Calories != round2(original[i].Calories*item.Portion) || item.Weight != round2(original[i].Weight*item.Portion) {
+					t.Errorf("items[%d] = %+v, nutrition is not scaled to the portion", i, item)
+				}
+			}
+		})
+	}
+}
+
+func TestWithinTargets(t *testing.T) {
+	targets := mealTargets{Calories: 2000, Sugar: 25, Carbohydrates: 250}
+
+	tests := []struct {
+		name   string
+		totals mealTargets
+		want   bool
+	}{
+		{"on target", mealTargets{Calories: 2000, Sugar: 20, Carbohydrates: 200}, true},
+		{"lowest calories allowed", mealTargets{Calories: 1800, Sugar: 20, Carbohydrates: 200}, true},
+		{"highest calories allowed", mealTargets{Calories: 2100, Sugar: 20, Carbohydrates: 200}, true},
+		{"too few calories", mealTargets{Calories: 1700, Sugar: 20, Carbohydrates: 200}, false},
+		{"too many calories", mealTargets{Calories: 2200, Sugar: 20, Carbohydrates: 200}, false},
+		{"too much sugar", mealTargets{Calories: 2000, Sugar: 30, Carbohydrates: 200}, false},
+		{"too many carbohydrates", mealTargets{Calories: 2000, Sugar: 20, Carbohydrates: 260}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withinTargets(tt.totals, targets); got != tt.want {
+				t.Errorf("withinTargets(%+v) = %t, want %t", tt.totals, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,369 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+	apperrors "github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	helper "github.com/rizkirmdhnnn/sweetlife-backend-go/helpers"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/repositories"
+	"gorm.io/gorm"
+)
+
+type RecipeService interface {
+	CreateRecipe(userID string, req *dto.RecipeRequest) (*dto.RecipeResponse, error)
+	GetRecipes(userID string) ([]dto.RecipeResponse, error)
+	GetRecipe(userID string, id uint) (*dto.RecipeResponse, error)
+	UpdateRecipe(userID string, id uint, req *dto.RecipeRequest) (*dto.RecipeResponse, error)
+}
+
+type recipeService struct {
+	recipeRepo     repositories.RecipeRepository
+	customFoodRepo repositories.CustomFoodRepository
+}
+
+func NewRecipeService(recipeRepo repositories.RecipeRepository, customFoodRepo repositories.CustomFoodRepository) RecipeService {
+	if recipeRepo == nil {
+		panic("recipeRepo cannot be nil")
+	}
+	if customFoodRepo == nil {
+		panic("customFoodRepo cannot be nil")
+	}
+	return &recipeService{
+		recipeRepo:     recipeRepo,
+		customFoodRepo: customFoodRepo,
+	}
+}
+
+// CreateRecipe implements RecipeService.
+// Recipes are private unless the user shares them.
+func (s *recipeService) CreateRecipe(userID string, req *dto.RecipeRequest) (*dto.RecipeResponse, error) {
+	recipe := models.Recipe{
+		OwnerID: userID,
+		Food:    models.Food{OwnerID: &userID},
+	}
+	var nutrition models.FoodNutrition
+	foods, err := s.applyRecipe(userID, &recipe, &nutrition, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.recipeRepo.CreateRecipe(&recipe, &nutrition); err != nil {
+		return nil, err
+	}
+	return toRecipeResponse(&recipe, foods, userID), nil
+}
+
+// GetRecipes implements RecipeService.
+// Only the recipes of the user are returned, shared recipes of others are found with the food search.
+func (s *recipeService) GetRecipes(userID string) ([]dto.RecipeResponse, error) {
+	recipes, err := s.recipeRepo.GetRecipesByOwner(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.RecipeResponse, 0, len(recipes))
+	for i := range recipes {
+		foods, err := getIngredientFoods(s.recipeRepo, recipes[i].Ingredients, userID)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *toRecipeResponse(&recipes[i], foods, userID))
+	}
+	return result, nil
+}
+
+// GetRecipe implements RecipeService.
+// A recipe can be viewed by its owner, or by anyone when it is shared.
+func (s *recipeService) GetRecipe(userID string, id uint) (*dto.RecipeResponse, error) {
+	recipe, foods, err := getVisibleRecipe(s.recipeRepo, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return toRecipeResponse(recipe, foods, userID), nil
+}
+
+// UpdateRecipe implements RecipeService.
+// Only the owner can update a recipe, the nutrition per serving is recalculated.
+func (s *recipeService) UpdateRecipe(userID string, id uint, req *dto.RecipeRequest) (*dto.RecipeResponse, error) {
+	recipe, err := s.recipeRepo.GetRecipeByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrRecipeNotFound()
+		}
+		return nil, err
+	}
+	if recipe.OwnerID != userID {
+		return nil, apperrors.ErrRecipeNotFound()
+	}
+
+	nutrition, err := s.recipeRepo.GetFoodNutrition(recipe.FoodID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if nutrition == nil {
+		nutrition = &models.FoodNutrition{FoodID: recipe.FoodID}
+	}
+
+	foods, err := s.applyRecipe(userID, recipe, nutrition, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.recipeRepo.UpdateRecipe(recipe, nutrition); err != nil {
+		return nil, err
+	}
+	// recipes using this recipe as an ingredient change with it
+	if err := refreshRecipes(s.recipeRepo, recipe.FoodID); err != nil {
+		return nil, err
+	}
+	return toRecipeResponse(recipe, foods, userID), nil
+}
+
+// getVisibleRecipe returns a recipe owned by the user or shared by its owner, with the foods of
+// its ingredients as the owner sees them. A shared recipe is only visible to others while all its
+// ingredients are public, its nutrition would give away a food made private after sharing.
+func getVisibleRecipe(recipeRepo repositories.RecipeRepository, userID string, id uint) (*models.Recipe, map[uint]models.FoodWithNutritions, error) {
+	recipe, err := recipeRepo.GetRecipeByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, apperrors.ErrRecipeNotFound()
+		}
+		return nil, nil, err
+	}
+	if recipe.OwnerID != userID && recipe.Food.Visibility != models.FoodVisibilityPublic {
+		return nil, nil, apperrors.ErrRecipeNotFound()
+	}
+
+	foods, err := getIngredientFoods(recipeRepo, recipe.Ingredients, recipe.OwnerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if recipe.OwnerID != userID && !publicIngredients(recipe.Ingredients, foods) {
+		return nil, nil, apperrors.ErrRecipeNotFound()
+	}
+	return recipe, foods, nil
+}
+
+// getIngredientFoods returns the foods of the ingredients visible to the user by food ID
+func getIngredientFoods(recipeRepo repositories.RecipeRepository, ingredients []models.RecipeIngredient, userID string) (map[uint]models.FoodWithNutritions, error) {
+	ids := make([]uint, 0, len(ingredients))
+	for _, ingredient := range ingredients {
+		ids = append(ids, ingredient.FoodID)
+	}
+
+	foods, err := recipeRepo.GetFoodsByIDs(ids, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	foodByID := make(map[uint]models.FoodWithNutritions, len(foods))
+	for _, food := range foods {
+		if _, found := foodByID[food.Food.ID]; !found {
+			foodByID[food.Food.ID] = food
+		}
+	}
+	return foodByID, nil
+}
+
+// publicIngredients reports whether every ingredient is a known public food
+func publicIngredients(ingredients []models.RecipeIngredient, foods map[uint]models.FoodWithNutritions) bool {
+	for _, ingredient := range ingredients {
+		food, found := foods[ingredient.FoodID]
+		if !found || food.Food.Visibility != models.FoodVisibilityPublic {
+			return false
+		}
+	}
+	return true
+}
+
+// refreshRecipes recalculates the nutrition of the recipes using a food after the food changed.
+// A shared recipe is made private when one of its ingredients is no longer public.
+// Recipes used as an ingredient of other recipes are refreshed in turn.
+func refreshRecipes(recipeRepo repositories.RecipeRepository, foodID uint) error {
+	refreshed := make(map[uint]bool)
+	queue := []uint{foodID}
+	for len(queue) > 0 {
+		recipes, err := recipeRepo.GetRecipesByIngredient(queue[0])
+		if err != nil {
+			return err
+		}
+		queue = queue[1:]
+
+		for i := range recipes {
+			recipe := &recipes[i]
+			if refreshed[recipe.ID] {
+				continue
+			}
+			refreshed[recipe.ID] = true
+
+			foods, err := getIngredientFoods(recipeRepo, recipe.Ingredients, recipe.OwnerID)
+			if err != nil {
+				return err
+			}
+			nutrition, err := recipeRepo.GetFoodNutrition(recipe.FoodID)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if nutrition == nil {
+				nutrition = &models.FoodNutrition{FoodID: recipe.FoodID}
+			}
+
+			setServingNutrition(nutrition, recipe.Ingredients, foods, recipe.Servings)
+			if recipe.Food.Visibility == models.FoodVisibilityPublic && !publicIngredients(recipe.Ingredients, foods) {
+				recipe.Food.Visibility = models.FoodVisibilityPrivate
+			}
+			if err := recipeRepo.UpdateRecipe(recipe, nutrition); err != nil {
+				return err
+			}
+			queue = append(queue, recipe.FoodID)
+		}
+	}
+	return nil
+}
+
+// applyRecipe validates a recipe request and copies it to the recipe, its food and the
+// nutrition of one serving. The foods of the ingredients are returned by food ID.
+func (s *recipeService) applyRecipe(userID string, recipe *models.Recipe, nutrition *models.FoodNutrition, req *dto.RecipeRequest) (map[uint]models.FoodWithNutritions, error) {
+	name := strings.TrimSpace(req.Name)
+	servings := req.Servings
+	if servings == 0 {
+		servings = 1
+	}
+	if name == "" || servings < 0 || len(req.Ingredients) == 0 {
+		return nil, apperrors.ErrInvalidRecipe()
+	}
+
+	visibility := models.FoodVisibilityPrivate
+	switch models.FoodVisibility(req.Visibility) {
+	case "", models.FoodVisibilityPrivate:
+	case models.FoodVisibilityPublic:
+		visibility = models.FoodVisibilityPublic
+	default:
+		return nil, apperrors.ErrInvalidRecipe()
+	}
+
+	ingredients := make([]models.RecipeIngredient, 0, len(req.Ingredients))
+	for _, ingredient := range req.Ingredients {
+		// a recipe can not contain itself
+		if ingredient.Weight <= 0 || (recipe.FoodID != 0 && ingredient.FoodID == recipe.FoodID) {
+			return nil, apperrors.ErrInvalidRecipe()
+		}
+		ingredients = append(ingredients, models.RecipeIngredient{
+			FoodID: ingredient.FoodID,
+			Weight: ingredient.Weight,
+		})
+	}
+
+	exists, err := s.customFoodRepo.CustomFoodNameExists(userID, name, recipe.FoodID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, apperrors.ErrRecipeExists()
+	}
+
+	// every ingredient must be a food the user can see
+	foods, err := getIngredientFoods(s.recipeRepo, ingredients, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, ingredient := range ingredients {
+		food, found := foods[ingredient.FoodID]
+		if !found {
+			return nil, apperrors.ErrFoodNotFound()
+		}
+		// the nutrition of a shared recipe would give away the private foods in it
+		if visibility == models.FoodVisibilityPublic && food.Food.Visibility != models.FoodVisibilityPublic {
+			return nil, apperrors.ErrRecipePrivateIngredient()
+		}
+	}
+
+	recipe.Food.Name = name
+	recipe.Food.Visibility = visibility
+	recipe.Servings = servings
+	recipe.Ingredients = ingredients
+
+	setServingNutrition(nutrition, ingredients, foods, servings)
+	return foods, nil
+}
+
+// setServingNutrition sets the nutrition of the recipe food to the nutrition of one serving
+func setServingNutrition(nutrition *models.FoodNutrition, ingredients []models.RecipeIngredient, foods map[uint]models.FoodWithNutritions, servings float64) {
+	if servings <= 0 {
+		servings = 1
+	}
+	total, _ := recipeNutrition(ingredients, foods)
+	perServing := helper.CalculateNutrients(total.Weight/servings, &total)
+	nutrition.Weight = perServing.Weight
+	nutrition.Calories = perServing.Calories
+	nutrition.Proteins = perServing.Proteins
+	nutrition.Sugar = perServing.Sugar
+	nutrition.Carbohydrates = perServing.Carbohydrates
+	nutrition.Fat = perServing.Fat
+}
+
+// recipeNutrition sums the nutrition of the ingredients, each scaled to its weight.
+// Ingredients without a known food are left out.
+func recipeNutrition(ingredients []models.RecipeIngredient, foods map[uint]models.FoodWithNutritions) (models.FoodNutrition, []dto.RecipeIngredientResponse) {
+	var total models.FoodNutrition
+	lines := make([]dto.RecipeIngredientResponse, 0, len(ingredients))
+	for _, ingredient := range ingredients {
+		food, found := foods[ingredient.FoodID]
+		if !found {
+			continue
+		}
+
+		nutrition := helper.CalculateNutrients(ingredient.Weight, &food.Nutrition)
+		total.Weight += nutrition.Weight
+		total.Calories += nutrition.Calories
+		total.Proteins += nutrition.Proteins
+		total.Sugar += nutrition.Sugar
+		total.Carbohydrates += nutrition.Carbohydrates
+		total.Fat += nutrition.Fat
+
+		lines = append(lines, dto.RecipeIngredientResponse{
+			FoodID:          food.Food.ID,
+			Name:            food.Food.Name,
+			RecipeNutrition: toRecipeNutrition(&nutrition),
+		})
+	}
+	return total, lines
+}
+
+func toRecipeNutrition(nutrition *models.FoodNutrition) dto.RecipeNutrition {
+	return dto.RecipeNutrition{
+		Weight:        round2(nutrition.Weight),
+		Calories:      round2(nutrition.Calories),
+		Protein:       round2(nutrition.Proteins),
+		Sugar:         round2(nutrition.Sugar),
+		Carbohydrates: round2(nutrition.Carbohydrates),
+		Fat:           round2(nutrition.Fat),
+	}
+}
+
+func toRecipeResponse(recipe *models.Recipe, foods map[uint]models.FoodWithNutritions, userID string) *dto.RecipeResponse {
+	total, lines := recipeNutrition(recipe.Ingredients, foods)
+
+	servings := recipe.Servings
+	if servings <= 0 {
+		servings = 1
+	}
+	perServing := helper.CalculateNutrients(total.Weight/servings, &total)
+
+	return &dto.RecipeResponse{
+		ID:          recipe.ID,
+		FoodID:      recipe.FoodID,
+		Name:        recipe.Food.Name,
+		Visibility:  string(recipe.Food.Visibility),
+		Owned:       recipe.OwnerID == userID,
+		Servings:    servings,
+		Total:       toRecipeNutrition(&total),
+		PerServing:  toRecipeNutrition(&perServing),
+		Ingredients: lines,
+		CreatedAt:   recipe.CreatedAt,
+		UpdatedAt:   recipe.UpdatedAt,
+	}
+}
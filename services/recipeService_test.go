@@ -0,0 +1,283 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+	apperrors "github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"gorm.io/gorm"
+)
+
+func (m *memFoodStore) saveRecipe(recipe *models.Recipe, nutrition *models.FoodNutrition) {
+	m.saveFood(&recipe.Food, nutrition)
+	recipe.FoodID = recipe.Food.ID
+	if recipe.ID == 0 {
+		recipe.ID = m.id()
+	}
+	stored := *recipe
+	stored.Ingredients = append([]models.RecipeIngredient(nil), recipe.Ingredients...)
+	m.recipes[recipe.ID] = stored
+}
+
+func (m *memFoodStore) recipe(id uint) models.Recipe {
+	recipe := m.recipes[id]
+	recipe.Food = m.foods[recipe.FoodID].Food
+	recipe.Ingredients = append([]models.RecipeIngredient(nil), recipe.Ingredients...)
+	return recipe
+}
+
+// memRecipeRepo is a RecipeRepository on a memFoodStore
+type memRecipeRepo struct{ *memFoodStore }
+
+func (r memRecipeRepo) CreateRecipe(recipe *models.Recipe, nutrition *models.FoodNutrition) error {
+	r.saveRecipe(recipe, nutrition)
+	return nil
+}
+
+func (r memRecipeRepo) UpdateRecipe(recipe *models.Recipe, nutrition *models.FoodNutrition) error {
+	r.saveRecipe(recipe, nutrition)
+	return nil
+}
+
+func (r memRecipeRepo) GetRecipeByID(id uint) (*models.Recipe, error) {
+	if _, found := r.recipes[id]; !found {
+		return nil, gorm.ErrRecordNotFound
+	}
+	recipe := r.recipe(id)
+	return &recipe, nil
+}
+
+func (r memRecipeRepo) GetRecipesByOwner(userID string) ([]models.Recipe, error) {
+	var recipes []models.Recipe
+	for id, recipe := range r.recipes {
+		if recipe.OwnerID == userID {
+			recipes = append(recipes, r.recipe(id))
+		}
+	}
+	return recipes, nil
+}
+
+func (r memRecipeRepo) GetRecipesByIngredient(foodID uint) ([]models.Recipe, error) {
+	var recipes []models.Recipe
+	for id, recipe := range r.recipes {
+		for _, ingredient := range recipe.Ingredients {
+			if ingredient.FoodID == foodID {
+				recipes = append(recipes, r.recipe(id))
+				break
+			}
+		}
+	}
+	return recipes, nil
+}
+
+func (r memRecipeRepo) GetFoodsByIDs(ids []uint, userID string) ([]models.FoodWithNutritions, error) {
+	var foods []models.FoodWithNutritions
+	for _, id := range ids {
+		food, found := r.foods[id]
+		if found && (food.Food.Visibility == models.FoodVisibilityPublic || (food.Food.OwnerID != nil && *food.Food.OwnerID == userID)) {
+			foods = append(foods, food)
+		}
+	}
+	return foods, nil
+}
+
+func (r memRecipeRepo) GetFoodNutrition(foodID uint) (*models.FoodNutrition, error) {
+	food, found := r.foods[foodID]
+	if !found {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &food.Nutrition, nil
+}
+
+// newRecipeTest returns the services on one store with a catalog rice of 130 kcal per 100 g
+// and a public custom sambal of the owner with 50 kcal per 100 g
+func newRecipeTest(t *testing.T) (RecipeService, CustomFoodService, *memFoodStore, uint, uint) {
+	t.Helper()
+	store := newMemFoodStore()
+	rice := models.Food{Name: "Rice", Visibility: models.FoodVisibilityPublic}
+	store.saveFood(&rice, &models.FoodNutrition{Calories: 130, Carbohydrates: 28, Weight: 100})
+
+	customFoods := NewCustomFoodService(memCustomFoodRepo{store}, memRecipeRepo{store})
+	sambal, err := customFoods.CreateCustomFood(foodOwner, &dto.CustomFoodRequest{Name: "Sambal", Calories: 50, Visibility: "public"})
+	if err != nil {
+		t.Fatalf("CreateCustomFood() error = %v", err)
+	}
+	return NewRecipeService(memRecipeRepo{store}, memCustomFoodRepo{store}), customFoods, store, rice.ID, sambal.ID
+}
+
+func TestCreateRecipeNutrition(t *testing.T) {
+	recipes, _, store, rice, sambal := newRecipeTest(t)
+
+	recipe, err := recipes.CreateRecipe(foodOwner, &dto.RecipeRequest{
+		Name:     "Nasi Sambal",
+		Servings: 2,
+		Ingredients: []dto.RecipeIngredientRequest{
+			{FoodID: rice, Weight: 300},
+			{FoodID: sambal, Weight: 100},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateRecipe() error = %v", err)
+	}
+
+	if recipe.Total.Weight != 400 || recipe.Total.Calories != 440 || recipe.Total.Carbohydrates != 84 {
+		t.Errorf("Total = %+v, want 400 g with 440 kcal and 84 g carbohydrates", recipe.Total)
+	}
+	if recipe.PerServing.Weight != 200 || recipe.PerServing.Calories != 220 {
+		t.Errorf("PerServing = %+v, want 200 g with 220 kcal", recipe.PerServing)
+	}
+	if recipe.Visibility != string(models.FoodVisibilityPrivate) {
+		t.Errorf("Visibility = %s, want private by default", recipe.Visibility)
+	}
+
+	// the recipe food holds one serving, so it is logged like any other food
+	nutrition := store.foods[recipe.FoodID].Nutrition
+	if nutrition.Weight != 200 || nutrition.Calories != 220 {
+		t.Errorf("recipe food nutrition = %+v, want one serving of 200 g with 220 kcal", nutrition)
+	}
+}
+
+func TestCreateRecipeRejectsInvalidIngredients(t *testing.T) {
+	recipes, customFoods, _, rice, _ := newRecipeTest(t)
+	private, err := customFoods.CreateCustomFood(foodOwner, &dto.CustomFoodRequest{Name: "Secret Sauce", Calories: 80})
+	if err != nil {
+		t.Fatalf("CreateCustomFood() error = %v", err)
+	}
+	others, err := customFoods.CreateCustomFood(foodViewer, &dto.CustomFoodRequest{Name: "Viewer Sauce", Calories: 80})
+	if err != nil {
+		t.Fatalf("CreateCustomFood() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		req     dto.RecipeRequest
+		wantErr error
+	}{
+		{"no weight", dto.RecipeRequest{Name: "A", Ingredients: []dto.RecipeIngredientRequest{{FoodID: rice}}}, apperrors.ErrInvalidRecipe()},
+		{"unknown food", dto.RecipeRequest{Name: "B", Ingredients: []dto.RecipeIngredientRequest{{FoodID: 999, Weight: 10}}}, apperrors.ErrFoodNotFound()},
+		{"private food of another user", dto.RecipeRequest{Name: "C", Ingredients: []dto.RecipeIngredientRequest{{FoodID: others.ID, Weight: 10}}}, apperrors.ErrFoodNotFound()},
+		{"shared with a private food", dto.RecipeRequest{Name: "D", Visibility: "public", Ingredients: []dto.RecipeIngredientRequest{{FoodID: private.ID, Weight: 10}}}, apperrors.ErrRecipePrivateIngredient()},
+		{"name of a custom food", dto.RecipeRequest{Name: "sambal", Ingredients: []dto.RecipeIngredientRequest{{FoodID: rice, Weight: 10}}}, apperrors.ErrRecipeExists()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := recipes.CreateRecipe(foodOwner, &tt.req)
+			if err == nil || err.Error() != tt.wantErr.Error() {
+				t.Errorf("CreateRecipe() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRecipeFollowsIngredientChanges(t *testing.T) {
+	recipes, customFoods, store, rice, sambal := newRecipeTest(t)
+	recipe, err := recipes.CreateRecipe(foodOwner, &dto.RecipeRequest{
+		Name:        "Nasi Sambal",
+		Visibility:  "public",
+		Ingredients: []dto.RecipeIngredientRequest{{FoodID: rice, Weight: 100}, {FoodID: sambal, Weight: 100}},
+	})
+	if err != nil {
+		t.Fatalf("CreateRecipe() error = %v", err)
+	}
+	// a recipe using the recipe follows it as well
+	platter, err := recipes.CreateRecipe(foodOwner, &dto.RecipeRequest{
+		Name:        "Platter",
+		Visibility:  "public",
+		Ingredients: []dto.RecipeIngredientRequest{{FoodID: recipe.FoodID, Weight: 200}},
+	})
+	if err != nil {
+		t.Fatalf("CreateRecipe() error = %v", err)
+	}
+
+	if _, err := customFoods.UpdateCustomFood(foodOwner, sambal, &dto.CustomFoodRequest{Name: "Sambal", Calories: 150, Visibility: "public"}); err != nil {
+		t.Fatalf("UpdateCustomFood() error = %v", err)
+	}
+	for _, foodID := range []uint{recipe.FoodID, platter.FoodID} {
+		if calories := store.foods[foodID].Nutrition.Calories; math.Abs(calories-280) > 1e-9 {
+			t.Errorf("food %d calories = %v after the ingredient changed, want 280", foodID, calories)
+		}
+	}
+
+	// a shared recipe stops being shared when an ingredient is made private
+	if _, err := customFoods.UpdateCustomFood(foodOwner, sambal, &dto.CustomFoodRequest{Name: "Sambal", Calories: 150}); err != nil {
+		t.Fatalf("UpdateCustomFood() error = %v", err)
+	}
+	for _, foodID := range []uint{recipe.FoodID, platter.FoodID} {
+		if visibility := store.foods[foodID].Food.Visibility; visibility != models.FoodVisibilityPrivate {
+			t.Errorf("food %d visibility = %s after an ingredient was made private, want private", foodID, visibility)
+		}
+	}
+	if _, err := recipes.GetRecipe(foodViewer, recipe.ID); err == nil || err.Error() != apperrors.ErrRecipeNotFound().Error() {
+		t.Errorf("GetRecipe() by another user error = %v, want %v", err, apperrors.ErrRecipeNotFound())
+	}
+	if _, err := recipes.GetRecipe(foodOwner, recipe.ID); err != nil {
+		t.Errorf("GetRecipe() by the owner error = %v", err)
+	}
+}
+
+func TestGetRecipeSharing(t *testing.T) {
+	recipes, _, store, rice, sambal := newRecipeTest(t)
+	shared, err := recipes.CreateRecipe(foodOwner, &dto.RecipeRequest{
+		Name:        "Nasi Sambal",
+		Visibility:  "public",
+		Ingredients: []dto.RecipeIngredientRequest{{FoodID: rice, Weight: 100}, {FoodID: sambal, Weight: 100}},
+	})
+	if err != nil {
+		t.Fatalf("CreateRecipe() error = %v", err)
+	}
+	private, err := recipes.CreateRecipe(foodOwner, &dto.RecipeRequest{
+		Name:        "Nasi Putih",
+		Ingredients: []dto.RecipeIngredientRequest{{FoodID: rice, Weight: 100}},
+	})
+	if err != nil {
+		t.Fatalf("CreateRecipe() error = %v", err)
+	}
+
+	got, err := recipes.GetRecipe(foodViewer, shared.ID)
+	if err != nil {
+		t.Fatalf("GetRecipe() of a shared recipe error = %v", err)
+	}
+	if got.Owned || len(got.Ingredients) != 2 || got.Total.Calories != 180 {
+		t.Errorf("GetRecipe() = %+v, want the shared recipe with both ingredients", got)
+	}
+
+	if _, err := recipes.GetRecipe(foodViewer, private.ID); err == nil || err.Error() != apperrors.ErrRecipeNotFound().Error() {
+		t.Errorf("GetRecipe() of a private recipe error = %v, want %v", err, apperrors.ErrRecipeNotFound())
+	}
+
+	// the invariant is checked on read as well, e.g. for recipes shared before it was enforced
+	sauce := store.foods[sambal]
+	sauce.Food.Visibility = models.FoodVisibilityPrivate
+	store.foods[sambal] = sauce
+	if _, err := recipes.GetRecipe(foodViewer, shared.ID); err == nil || err.Error() != apperrors.ErrRecipeNotFound().Error() {
+		t.Errorf("GetRecipe() of a recipe with a private ingredient error = %v, want %v", err, apperrors.ErrRecipeNotFound())
+	}
+}
+
+func TestUpdateRecipeOnlyByOwner(t *testing.T) {
+	recipes, _, _, rice, _ := newRecipeTest(t)
+	recipe, err := recipes.CreateRecipe(foodOwner, &dto.RecipeRequest{
+		Name:        "Nasi Putih",
+		Visibility:  "public",
+		Ingredients: []dto.RecipeIngredientRequest{{FoodID: rice, Weight: 100}},
+	})
+	if err != nil {
+		t.Fatalf("CreateRecipe() error = %v", err)
+	}
+
+	req := &dto.RecipeRequest{Name: "Nasi Putih", Ingredients: []dto.RecipeIngredientRequest{{FoodID: rice, Weight: 200}}}
+	if _, err := recipes.UpdateRecipe(foodViewer, recipe.ID, req); err == nil || err.Error() != apperrors.ErrRecipeNotFound().Error() {
+		t.Errorf("UpdateRecipe() by another user error = %v, want %v", err, apperrors.ErrRecipeNotFound())
+	}
+
+	updated, err := recipes.UpdateRecipe(foodOwner, recipe.ID, req)
+	if err != nil {
+		t.Fatalf("UpdateRecipe() error = %v", err)
+	}
+	if updated.PerServing.Calories != 260 {
+		t.Errorf("PerServing.Calories = %v, want 260", updated.PerServing.Calories)
+	}
+}
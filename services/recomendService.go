@@ -3,47 +3,67 @@ package services
 import (
 	"errors"
 	"fmt"
+	"log"
+	"sort"
+	"strings"
 
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+	apperrors "github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	helper "github.com/rizkirmdhnnn/sweetlife-backend-go/helpers"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/repositories"
 	"gorm.io/gorm"
 )
 
 type RecomendationService interface {
 	GetFoodRecomendations(userid string) ([]*dto.FoodRecomendation, error)
-	GetExerciseRecomendations(userid string) (*dto.ExerciseRecommendation, error)
+	GetExerciseRecomendations(userid, lang string) (*dto.ExerciseRecommendation, error)
+	SaveFeedback(userid string, req *dto.RecommendationFeedbackRequest) error
+	AteFood(userid string, req *dto.AteFoodRequest) error
 }
 
 type recomendationService struct {
 	recomendationRepo repositories.RecomendationRepo
 	healthRepo        repositories.HealthProfileRepository
 	authRepo          repositories.AuthRepository
+	exerciseRepo      repositories.ExerciseRepository
+	foodTagRepo       repositories.FoodTagRepository
+	feedbackRepo      repositories.FeedbackRepository
+	scanRepo          repositories.ScanFoodRepository
 }
 
-func NewRecomendationService(recomendationRepo repositories.RecomendationRepo, healthRepo repositories.HealthProfileRepository, authRepo repositories.AuthRepository) RecomendationService {
+func NewRecomendationService(recomendationRepo repositories.RecomendationRepo, healthRepo repositories.HealthProfileRepository, authRepo repositories.AuthRepository, exerciseRepo repositories.ExerciseRepository, foodTagRepo repositories.FoodTagRepository, feedbackRepo repositories.FeedbackRepository, scanRepo repositories.ScanFoodRepository) RecomendationService {
 	if recomendationRepo == nil {
 		panic("recomendationRepo cannot be nil")
 	}
+	if exerciseRepo == nil {
+		panic("exerciseRepo cannot be nil")
+	}
+	if foodTagRepo == nil {
+		panic("foodTagRepo cannot be nil")
+	}
+	if feedbackRepo == nil {
+		panic("feedbackRepo cannot be nil")
+	}
+	if scanRepo == nil {
+		panic("scanRepo cannot be nil")
+	}
 	return &recomendationService{
 		recomendationRepo: recomendationRepo,
 		healthRepo:        healthRepo,
 		authRepo:          authRepo,
+		exerciseRepo:      exerciseRepo,
+		foodTagRepo:       foodTagRepo,
+		feedbackRepo:      feedbackRepo,
+		scanRepo:          scanRepo,
 	}
 }
 
 // GetRecomendations implements RecomendationService.
 func (r *recomendationService) GetFoodRecomendations(userid string) ([]*dto.FoodRecomendation, error) {
-	healthProfile, err := r.healthRepo.GetRiskAssessmentByUserID(userid)
-	var riskScore float32
-
+	riskScore, err := getRiskScore(r.healthRepo, userid)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			riskScore = 100
-		} else {
-			return nil, err
-		}
-	} else {
-		riskScore = float32(healthProfile.RiskScore)
+		return nil, err
 	}
 
 	// 2. Get recommendations
@@ -51,9 +71,29 @@ func (r *recomendationService) GetFoodRecomendations(userid string) ([]*dto.Food
 	if err != nil {
 		return nil, err
 	}
+	var names []string
+	for _, foodList := range foodRecomendationClientResp.FoodRecomendation {
+		for _, food := range foodList {
+			names = append(names, food.Name)
+		}
+	}
+
+	// 3. Leave out foods the user can not eat or did not like
+	diet, err := newDietaryFilter(r.healthRepo, r.foodTagRepo, userid, names)
+	if err != nil {
+		return nil, err
+	}
+	feedback, err := r.feedbackRepo.GetFeedbackByUser(userid, models.FoodFeedback)
+	if err != nil {
+		return nil, err
+	}
+
 	var foodRecomendations []*dto.FoodRecomendation
 	for _, foodList := range foodRecomendationClientResp.FoodRecomendation {
 		for _, food := range foodList {
+			if !diet.allows(food.Name) || feedback[strings.ToLower(food.Name)] == models.DislikeRating {
+				continue
+			}
 			foodRec := dto.FoodRecomendation{
 				Name: food.Name,
 				Details: dto.RecomendationDetails{
@@ -68,11 +108,21 @@ func (r *recomendationService) GetFoodRecomendations(userid string) ([]*dto.Food
 		}
 	}
 
+	// liked foods go first, then foods confirmed to fit the preferences, the ML order is kept otherwise
+	sort.SliceStable(foodRecomendations, func(i, j int) bool {
+		likedI := feedback[strings.ToLower(foodRecomendations[i].Name)] == models.LikeRating
+		likedJ := feedback[strings.ToLower(foodRecomendations[j].Name)] == models.LikeRating
+		if likedI != likedJ {
+			return likedI
+		}
+		return diet.score(foodRecomendations[i].Name) > diet.score(foodRecomendations[j].Name)
+	})
+
 	return foodRecomendations, nil
 }
 
 // GetExerciseRecomendations implements RecomendationService.
-func (r *recomendationService) GetExerciseRecomendations(userid string) (*dto.ExerciseRecommendation, error) {
+func (r *recomendationService) GetExerciseRecomendations(userid, lang string) (*dto.ExerciseRecommendation, error) {
 	// get user health profile
 	healthProfile, err := r.healthRepo.GetHealthProfileByUserID(userid)
 	if err != nil {
@@ -105,94 +155,58 @@ func (r *recomendationService) GetExerciseRecomendations(userid string) (*dto.Ex
 		return nil, err
 	}
 
-	exerciseData := []dto.ExerciseList{
-		{
-			Name:  "Squats",
-			Desc:  "A strength training exercise involving the thighs, hips, and buttocks muscles. Squats help improve lower body strength and core stability.",
-			Image: "https://storage.googleapis.com/sweetlife-go-new/website/exercise/Squats.jpg",
-		},
-		{
-			Name:  "Deadlifts",
-			Desc:  "A weightlifting exercise where you lift a weight from the floor to your hips. Deadlifts strengthen the lower back, thighs, buttocks, and upper back muscles.",
-			Image: "https://storage.googleapis.com/sweetlife-go-new/website/exercise/Deadlifts.jpg",
-		},
-		{
-			Name:  "Bench presses",
-			Desc:  "A strength exercise using a barbell or dumbbells, typically performed while lying on a bench. It focuses on strengthening the chest, shoulders, and triceps.",
-			Image: "https://storage.googleapis.com/sweetlife-go-new/website/exercise/Bench%20presses.jpg",
-		},
-		{
-			Name:  "Overhead presses",
-			Desc:  "A weightlifting exercise where you push a weight overhead from your shoulders. It helps train the shoulder muscles, triceps, and core stability.",
-			Image: "https://storage.googleapis.com/sweetlife-go-new/website/exercise/Overhead%20presses.jpg",
-		},
-		{
-			Name:  "Yoga",
-			Desc:  "A physical and mental practice combining body postures, breathing techniques, and meditation. Yoga improves flexibility, balance, strength, and reduces stress.",
-			Image: "https://storage.googleapis.com/sweetlife-go-new/website/exercise/Yoga.jpg",
-		},
-		{
-			Name:  "Brisk walking",
-			Desc:  "A fast-paced walk aimed at increasing heart rate. It's beneficial for heart health, calorie burning, and general fitness.",
-			Image: "https://storage.googleapis.com/sweetlife-go-new/website/exercise/Brisk%20walking.jpg",
-		},
-		{
-			Name:  "Cycling",
-			Desc:  "An activity involving pedaling a bicycle that works the leg muscles, strengthens cardiovascular health, and burns calories. It can be done outdoors or on a stationary bike.",
-			Image: "https://storage.googleapis.com/sweetlife-go-new/website/exercise/Cycling.jpg",
-		},
-		{
-			Name:  "Swimming",
-			Desc:  "A water sport that involves almost all the body's muscles. It helps improve endurance, breathing techniques, and protects joints due to low impact.",
-			Image: "https://storage.googleapis.com/sweetlife-go-new/website/exercise/Swimming.jpg",
-		},
-		{
-			Name:  "Running",
-			Desc:  "A cardiovascular exercise that helps improve heart health, endurance, and burns a significant amount of calories.",
-			Image: "https://storage.googleapis.com/sweetlife-go-new/website/exercise/Running.jpg",
-		},
-		{
-			Name:  "Dancing",
-			Desc:  "A physical activity involving rhythmic body movements to music. It's great for fitness, coordination, and mood improvement.",
-			Image: "https://storage.googleapis.com/sweetlife-go-new/website/exercise/Dancing.jpg",
-		},
-		{
-			Name:  "Walking",
-			Desc:  "A light activity that can be done by anyone. It helps improve blood circulation, reduce stress, and maintain heart health.",
-			Image: "https://storage.googleapis.com/sweetlife-go-new/website/exercise/Walking.jpg",
-		},
-	}
-
-	// Membuat map dari exerciseData untuk kemudahan pencarian berdasarkan nama
-	exerciseDataMap := make(map[string]dto.ExerciseList) // Perbaiki tipe data map
-	for _, exercise := range exerciseData {
-		exerciseDataMap[exercise.Name] = exercise
-	}
-
-	// List hasil gabungan
-	var exerciseList []*dto.ExerciseList
+	// get exercise details from catalog
+	exercises, err := r.exerciseRepo.GetExercisesByNames(exerciseRecomendationClientResp.ExerciseCategories)
+	if err != nil {
+		return nil, err
+	}
+
+	exerciseDataMap := make(map[string]models.Exercise)
+	for _, exercise := range exercises {
+		exerciseDataMap[strings.ToLower(exercise.Name)] = exercise
+	}
+
+	feedback, err := r.feedbackRepo.GetFeedbackByUser(userid, models.ExerciseFeedback)
+	if err != nil {
+		return nil, err
+	}
 
-	// Loop untuk menggabungkan data dari ExerciseCategories dengan ExerciseData
+	// merge ML categories with catalog data, disliked exercises are left out
+	var exerciseList []*dto.ExerciseList
 	for _, nameExercise := range exerciseRecomendationClientResp.ExerciseCategories {
-		// Membuat struct baru untuk ExerciseList
+		if feedback[strings.ToLower(nameExercise)] == models.DislikeRating {
+			continue
+		}
+
 		exeList := dto.ExerciseList{
 			Name: nameExercise,
 		}
 
-		// Jika nama olahraga ditemukan di map, ambil deskripsi dan gambar
-		if exercise, found := exerciseDataMap[nameExercise]; found {
-			exeList.Desc = exercise.Desc
-			exeList.Image = exercise.Image
-		} else {
-			// Jika nama olahraga tidak ada di exerciseData, tentukan default atau kosongkan
-			exeList.Desc = ""
-			exeList.Image = ""
+		exercise, found := exerciseDataMap[strings.ToLower(nameExercise)]
+		if !found {
+			log.Printf("exercise %q from ML service not found in catalog", nameExercise)
+			exerciseList = append(exerciseList, &exeList)
+			continue
+		}
+
+		exeList.ID = exercise.ID
+		exeList.Name = exercise.Name
+		exeList.Desc = exercise.Description
+		if lang == helper.LangIndonesian && exercise.DescriptionIndo != "" {
+			exeList.Desc = exercise.DescriptionIndo
 		}
+		exeList.MET = exercise.MET
+		exeList.Image = exercise.Image
 
-		// Menambahkan hasil gabungan ke dalam list
 		exerciseList = append(exerciseList, &exeList)
 	}
 
+	// liked exercises go first
+	sort.SliceStable(exerciseList, func(i, j int) bool {
+		return feedback[strings.ToLower(exerciseList[i].Name)] == models.LikeRating &&
+			feedback[strings.ToLower(exerciseList[j].Name)] != models.LikeRating
+	})
+
 	exerciseRecomendations := dto.ExerciseRecommendation{
 		CaloriesBurned:   exerciseRecomendationClientResp.CaloriesBurned,
 		ExerciseDuration: exerciseRecomendationClientResp.ExerciseDuration,
@@ -201,3 +215,145 @@ func (r *recomendationService) GetExerciseRecomendations(userid string) (*dto.Ex
 
 	return &exerciseRecomendations, nil
 }
+
+// SaveFeedback implements RecomendationService.
+func (r *recomendationService) SaveFeedback(userid string, req *dto.RecommendationFeedbackRequest) error {
+	if req.Type != models.FoodFeedback && req.Type != models.ExerciseFeedback {
+		return apperrors.ErrInvalidFeedback()
+	}
+
+	name := strings.ToLower(strings.TrimSpace(req.Name))
+	if name == "" {
+		return apperrors.ErrInvalidFeedback()
+	}
+
+	switch models.FeedbackRating(req.Rating) {
+	case models.LikeRating, models.DislikeRating:
+		return r.feedbackRepo.SaveFeedback(&models.RecommendationFeedback{
+			UserID:     userid,
+			TargetType: req.Type,
+			Name:       name,
+			Rating:     models.FeedbackRating(req.Rating),
+		})
+	case "none":
+		return r.feedbackRepo.DeleteFeedback(userid, req.Type, name)
+	default:
+		return apperrors.ErrInvalidFeedback()
+	}
+}
+
+// AteFood implements RecomendationService.
+// The food is logged to the food history of the user. Recommended foods missing from
+// the food table are added with the nutrition given by the ML service.
+func (r *recomendationService) AteFood(userid string, req *dto.AteFoodRequest) error {
+	food, err := r.scanRepo.SearchFoodFromDB(req.Name, "")
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		food, err = r.createRecommendedFood(userid, req.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	unit := req.Unit
+	if unit <= 0 {
+		unit = 1
+	}
+
+	histories := []models.UserFoodHistory{{
+		UserID: userid,
+		FoodID: food.Food.ID,
+		Unit:   unit,
+		Weight: req.Weight,
+	}}
+	return r.scanRepo.SaveUserFoodHistory(&histories)
+}
+
+// createRecommendedFood looks up a food in the current recommendations of a user and saves it to the food table.
+// The ML service gives nutrition for one serving of unknown weight, it can not be scaled to a
+// portion, so the nutrition of the food is looked up in the USDA API.
+func (r *recomendationService) createRecommendedFood(userid, name string) (*models.FoodWithNutritions, error) {
+	riskScore, err := getRiskScore(r.healthRepo, userid)
+	if err != nil {
+		return nil, err
+	}
+
+	recommendations, err := r.recomendationRepo.GetFoodRecomendations(riskScore)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range recommendations.FoodRecomendation {
+		for _, recommended := range group {
+			if !strings.EqualFold(recommended.Name, strings.TrimSpace(name)) {
+				continue
+			}
+
+			// the name may differ in case only from a food that already exists
+			if food, err := r.scanRepo.SearchFoodFromDB(recommended.Name, ""); err == nil {
+				return food, nil
+			}
+
+			return saveUSDAFood(r.scanRepo, &dto.FindFoodRequest{Name: recommended.Name})
+		}
+	}
+
+	return nil, apperrors.ErrFoodNotFound()
+}
+
+// getRiskScore returns the diabetes risk score of a user.
+// Users without a risk assessment are treated as high risk.
+func getRiskScore(healthRepo repositories.HealthProfileRepository, userID string) (float32, error) {
+	assessment, err := healthRepo.GetRiskAssessmentByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 100, nil
+		}
+		return 0, err
+	}
+	return float32(assessment.RiskScore), nil
+}
+
+// dietaryFilter matches foods against the dietary preferences of a user
+type dietaryFilter struct {
+	preferences []models.DietaryPreference
+	tags        map[string][]string
+}
+
+// newDietaryFilter loads the dietary preferences of a user and the tags of the given foods
+func newDietaryFilter(healthRepo repositories.HealthProfileRepository, foodTagRepo repositories.FoodTagRepository, userID string, names []string) (*dietaryFilter, error) {
+	preferences, err := healthRepo.GetDietaryPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &dietaryFilter{preferences: preferences}
+	if len(preferences) == 0 {
+		return filter, nil
+	}
+
+	filter.tags, err = foodTagRepo.GetTagsByFoodNames(names)
+	if err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// allows reports whether the food fits the dietary preferences
+func (f *dietaryFilter) allows(name string) bool {
+	if len(f.preferences) == 0 {
+		return true
+	}
+	return helper.IsFoodAllowed(f.tags[strings.ToLower(name)], f.preferences)
+}
+
+// score ranks foods confirmed to fit the dietary preferences higher
+func (f *dietaryFilter) score(name string) int {
+	if len(f.preferences) == 0 {
+		return 0
+	}
+	return helper.DietaryScore(f.tags[strings.ToLower(name)], f.preferences)
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/mlclient"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/mlstub"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/repositories"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/resilience"
+)
+
+// fakeHealthRepo returns the dietary preferences of every user
+type fakeHealthRepo struct {
+	repositories.HealthProfileRepository
+	preferences []models.DietaryPreference
+}
+
+func (r *fakeHealthRepo) GetDietaryPreferences(userID string) ([]models.DietaryPreference, error) {
+	return r.preferences, nil
+}
+
+// fileFoodTagRepo answers with the tags of data/food_tags.json
+type fileFoodTagRepo struct {
+	tags map[string][]string
+}
+
+func newFileFoodTagRepo(t *testing.T) *fileFoodTagRepo {
+	t.Helper()
+	data, err := os.ReadFile("../data/food_tags.json")
+	if err != nil {
+		t.Fatalf("read food tags: %v", err)
+	}
+	var foods []struct {
+		Food string   `json:"food"`
+		Tags []string `json:"tags"`
+	}
+	if err := json.Unmarshal(data, &foods); err != nil {
+		t.Fatalf("decode food tags: %v", err)
+	}
+
+	repo := &fileFoodTagRepo{tags: make(map[string][]string)}
+	for _, food := range foods {
+		repo.tags[strings.ToLower(food.Food)] = food.Tags
+	}
+	return repo
+}
+
+func (r *fileFoodTagRepo) GetTagsByFoodNames(names []string) (map[string][]string, error) {
+	result := make(map[string][]string)
+	for _, name := range names {
+		if tags, ok := r.tags[strings.ToLower(name)]; ok {
+			result[strings.ToLower(name)] = tags
+		}
+	}
+	return result, nil
+}
+
+// mlStubFoodNames returns the names of the foods the ML stub recommends
+func mlStubFoodNames(t *testing.T) []string {
+	t.Helper()
+	server := mlstub.NewServer()
+	t.Cleanup(server.Close)
+
+	upstream := resilience.Register(fmt.Sprintf("%s-%d", t.Name(), time.Now().UnixNano()), resilience.Settings{Retryable: mlclient.IsRetryable})
+	repo := repositories.NewRecomendationRepo(mlclient.NewClient(server.URL, &http.Client{Timeout: 5 * time.Second}), upstream)
+	resp, err := repo.GetFoodRecomendations(60)
+	if err != nil {
+		t.Fatalf("GetFoodRecomendations() error = %v", err)
+	}
+
+	var names []string
+	for _, group := range resp.FoodRecomendation {
+		for _, food := range group {
+			names = append(names, food.Name)
+		}
+	}
+	return names
+}
+
+func TestDietaryFilterRecommendations(t *testing.T) {
+	names := mlStubFoodNames(t)
+
+	tests := []struct {
+		name        string
+		preferences []models.DietaryPreference
+		want        []string
+	}{
+		{"no preferences", nil, []string{"Gado-gado", "Pepes Ikan", "Sayur Bening", "Tahu Bacem", "Ayam Bakar"}},
+		{"vegetarian", []models.DietaryPreference{models.Vegetarian}, []string{"Gado-gado", "Sayur Bening", "Tahu Bacem"}},
+		{"vegan", []models.DietaryPreference{models.Vegan}, []string{"Sayur Bening", "Tahu Bacem"}},
+		{"nut allergy", []models.DietaryPreference{models.NutAllergy}, []string{"Pepes Ikan", "Sayur Bening", "Tahu Bacem", "Ayam Bakar"}},
+		{"seafood allergy", []models.DietaryPreference{models.SeafoodAllergy}, []string{"Gado-gado", "Sayur Bening", "Tahu Bacem", "Ayam Bakar"}},
+		{"halal", []models.DietaryPreference{models.Halal}, []string{"Gado-gado", "Pepes Ikan", "Sayur Bening", "Tahu Bacem", "Ayam Bakar"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diet, err := newDietaryFilter(&fakeHealthRepo{preferences: tt.preferences}, newFileFoodTagRepo(t), "user", names)
+			if err != nil {
+				t.Fatalf("newDietaryFilter() error = %v", err)
+			}
+
+			var got []string
+			for _, name := range names {
+				if diet.allows(name) {
+					got = append(got, name)
+				}
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("allowed foods = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDietaryFilterUntaggedFood(t *testing.T) {
+	diet, err := newDietaryFilter(&fakeHealthRepo{preferences: []models.DietaryPreference{models.Vegetarian}}, newFileFoodTagRepo(t), "user", []string{"Rendang"})
+	if err != nil {
+		t.Fatalf("newDietaryFilter() error = %v", err)
+	}
+	if diet.allows("Rendang") {
+		t.Error("an untagged food is allowed for a vegetarian")
+	}
+}
@@ -1,76 +1,259 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"log"
+	"math"
 	"mime/multipart"
-	"os"
-	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/config"
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+	apperrors "github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
 	helper "github.com/rizkirmdhnnn/sweetlife-backend-go/helpers"
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
 	"github.com/rizkirmdhnnn/sweetlife-backend-go/repositories"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/worker"
+	"gorm.io/gorm"
 )
 
+// maxScanCandidates limits the foods suggested for a label missing from the catalog
+const maxScanCandidates = 5
+
+// scanStaleAfter is how long a scan can stay pending or processing, scans queued when
+// the server stopped are never processed and are failed after this time
+const scanStaleAfter = 15 * time.Minute
+
+// maxUSDACandidates limits the USDA foods offered for a food missing from the database
+const maxUSDACandidates = 10
+
 type ScanFoodService interface {
-	ScanFood(file *multipart.FileHeader) (*dto.ScanFoodResponse, error)
-	SearchFood(req *dto.FindFoodRequest) (*models.ScanFood, error)
+	ScanFood(file *multipart.FileHeader, userID string) (*dto.ScanFoodResponse, error)
+	ScanFoodAsync(file *multipart.FileHeader, userID string) (*dto.ScanJobResponse, error)
+	GetScan(id uint, userID string) (*dto.ScanJobResponse, error)
+	FailStaleScans() error
+	SearchFood(req *dto.FindFoodRequest, userID string) (*models.ScanFood, error)
+	FindFoodByBarcode(req *dto.BarcodeRequest) (*models.ScanFood, error)
+	SearchFoodCandidates(query string, limit int, userID string) ([]dto.FoodSearchResult, error)
+	SearchUSDAFoods(query string, limit int) ([]dto.FoodNutritionResponse, error)
 	SaveFood(req *dto.SaveFoodRequest, userId string) error
 }
 
 type scanFoodService struct {
 	scanRepo    repositories.ScanFoodRepository
 	storegeRepo repositories.StorageBucketRepository
+	catalogRepo repositories.FoodCatalogRepository
+	productRepo repositories.ProductRepository
+	recipeRepo  repositories.RecipeRepository
+	pool        *worker.Pool
 }
 
-func NewScanFoodService(scanRepo repositories.ScanFoodRepository, storageRepo repositories.StorageBucketRepository) ScanFoodService {
+func NewScanFoodService(scanRepo repositories.ScanFoodRepository, storageRepo repositories.StorageBucketRepository, catalogRepo repositories.FoodCatalogRepository, productRepo repositories.ProductRepository, recipeRepo repositories.RecipeRepository, pool *worker.Pool) ScanFoodService {
+	if catalogRepo == nil {
+		panic("catalogRepo cannot be nil")
+	}
+	if productRepo == nil {
+		panic("productRepo cannot be nil")
+	}
+	if recipeRepo == nil {
+		panic("recipeRepo cannot be nil")
+	}
+	if pool == nil {
+		panic("pool cannot be nil")
+	}
 	return &scanFoodService{
 		scanRepo:    scanRepo,
 		storegeRepo: storageRepo,
+		catalogRepo: catalogRepo,
+		productRepo: productRepo,
+		recipeRepo:  recipeRepo,
+		pool:        pool,
 	}
 }
 
-func (s *scanFoodService) ScanFood(file *multipart.FileHeader) (*dto.ScanFoodResponse, error) {
-	// Generate unique file name
-	fileName := helper.GenerateFileName(filepath.Ext(file.Filename))
-	uploadPath := "website/scan-food/"
-	image, err := file.Open()
+// ScanFood implements ScanFoodService.
+// Every scan is kept as a scan session, its ID is returned so the saved foods can refer to it.
+func (s *scanFoodService) ScanFood(file *multipart.FileHeader, userID string) (*dto.ScanFoodResponse, error) {
+	session, prepared, err := s.newScanSession(file, userID, models.ScanStatusProcessing)
 	if err != nil {
 		return nil, err
 	}
-	defer image.Close()
+	return s.processScan(session, prepared)
+}
 
-	// Upload file to storage
-	url, err := s.storegeRepo.UploadFile(context.Background(), config.ENV.STORAGE_BUCKET, uploadPath+fileName, image)
+// ScanFoodAsync implements ScanFoodService.
+// The image is validated right away, uploading and detecting is left to the worker pool.
+// The returned scan session can be polled with GetScan.
+func (s *scanFoodService) ScanFoodAsync(file *multipart.FileHeader, userID string) (*dto.ScanJobResponse, error) {
+	session, prepared, err := s.newScanSession(file, userID, models.ScanStatusPending)
 	if err != nil {
 		return nil, err
 	}
 
-	fmt.Println("URL: ", url)
+	// the response is built before queueing, the session belongs to the worker afterwards
+	job, err := scanJob(session)
+	if err != nil {
+		return nil, err
+	}
 
-	// Call ML API to scan food
-	scanFoodResponse, err := s.scanRepo.ScanFood(url)
+	queued := s.pool.Submit(func() {
+		if _, err := s.processScan(session, prepared); err != nil {
+			log.Printf("scan food: scan session %d failed: %v", session.ID, err)
+		}
+	})
+	if !queued {
+		s.failScan(session, apperrors.ErrScanQueueFull())
+		return nil, apperrors.ErrScanQueueFull()
+	}
+
+	return job, nil
+}
+
+// GetScan implements ScanFoodService.
+func (s *scanFoodService) GetScan(id uint, userID string) (*dto.ScanJobResponse, error) {
+	session, err := s.scanRepo.GetScanSessionByID(id)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrScanSessionNotFound()
+		}
 		return nil, err
 	}
+	if session.UserID != userID {
+		return nil, apperrors.ErrScanSessionNotFound()
+	}
+	if isStaleScan(session) {
+		s.failScan(session, apperrors.ErrScanFailed())
+	}
+	return scanJob(session)
+}
 
-	// Open file with nutrition data
-	fileNutrition, err := os.Open("data/nutritions.json")
+// FailStaleScans implements ScanFoodService.
+// It is called on startup, scans queued before a restart were lost with the queue.
+func (s *scanFoodService) FailStaleScans() error {
+	count, err := s.scanRepo.FailStaleScanSessions(time.Now().Add(-scanStaleAfter), apperrors.ErrScanFailed().Error())
 	if err != nil {
-		log.Fatalf("failed to open file: %v", err)
+		return err
 	}
-	defer fileNutrition.Close()
+	if count > 0 {
+		log.Printf("scan food: marked %d stale scan sessions as failed", count)
+	}
+	return nil
+}
 
-	// Parse the JSON data
-	var foodListClientResp []models.ScanFood
-	if err := json.NewDecoder(fileNutrition).Decode(&foodListClientResp); err != nil {
-		log.Fatalf("failed to decode JSON: %v", err)
+// isStaleScan reports whether a scan has been pending or processing for too long
+func isStaleScan(session *models.ScanSession) bool {
+	if session.Status != models.ScanStatusPending && session.Status != models.ScanStatusProcessing {
+		return false
+	}
+	return time.Since(session.UpdatedAt) > scanStaleAfter
+}
+
+// scanJob converts a scan session to its job state
+func scanJob(session *models.ScanSession) (*dto.ScanJobResponse, error) {
+	job := &dto.ScanJobResponse{
+		ScanSessionID: session.ID,
+		Status:        string(session.Status),
+		Error:         session.Error,
+	}
+	if session.Status == models.ScanStatusDone && session.Result != nil {
+		var result dto.ScanFoodResponse
+		if err := json.Unmarshal([]byte(*session.Result), &result); err != nil {
+			return nil, err
+		}
+		job.Result = &result
+	}
+	return job, nil
+}
+
+// newScanSession validates and prepares the image and creates the scan session for it
+func (s *scanFoodService) newScanSession(file *multipart.FileHeader, userID string, status models.ScanStatus) (*models.ScanSession, []byte, error) {
+	// Validate, strip metadata and downscale the image, it is always stored as jpg
+	if file.Size > helper.MaxScanImageSize {
+		return nil, nil, apperrors.ErrImageTooLarge()
+	}
+	image, err := file.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer image.Close()
+
+	prepared, err := helper.PrepareScanImage(image)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	session := &models.ScanSession{
+		UserID: userID,
+		Status: status,
+	}
+	if err := s.scanRepo.CreateScanSession(session); err != nil {
+		return nil, nil, err
+	}
+	return session, prepared, nil
+}
+
+// processScan uploads the image, detects the foods and matches them with the catalog.
+// The result or the error is kept in the scan session.
+func (s *scanFoodService) processScan(session *models.ScanSession, prepared []byte) (*dto.ScanFoodResponse, error) {
+	if session.Status != models.ScanStatusProcessing {
+		session.Status = models.ScanStatusProcessing
+		if err := s.scanRepo.UpdateScanSession(session); err != nil {
+			return nil, err
+		}
+	}
+
+	response, err := s.detectFoods(session, prepared)
+	if err != nil {
+		s.failScan(session, err)
+		return nil, err
+	}
+
+	result := marshalJSON(response)
+	session.Status = models.ScanStatusDone
+	session.Result = &result
+	if err := s.scanRepo.UpdateScanSession(session); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// failScan marks a scan session as failed, failures to save it are only logged.
+// The error is shown to the user, internal causes like upstream or database errors are replaced by a generic one.
+func (s *scanFoodService) failScan(session *models.ScanSession, cause error) {
+	message := apperrors.ErrScanFailed().Error()
+	if cause.Error() == apperrors.ErrScanQueueFull().Error() {
+		message = cause.Error()
+	}
+
+	session.Status = models.ScanStatusFailed
+	session.Error = message
+	if err := s.scanRepo.UpdateScanSession(session); err != nil {
+		log.Printf("scan food: failed to update scan session %d: %v", session.ID, err)
+	}
+}
+
+// detectFoods uploads the image and calls the ML service, the detected foods are kept in the scan session
+func (s *scanFoodService) detectFoods(session *models.ScanSession, prepared []byte) (*dto.ScanFoodResponse, error) {
+	// Generate unique file name
+	fileName := helper.GenerateFileName(".jpg")
+	uploadPath := "website/scan-food/"
+
+	// Upload file to storage
+	url, err := s.storegeRepo.UploadFile(context.Background(), config.ENV.STORAGE_BUCKET, uploadPath+fileName, bytes.NewReader(prepared))
+	if err != nil {
+		return nil, err
+	}
+	session.ImageObject = uploadPath + fileName
+	session.ImageURL = url
+
+	// Call ML API to scan food
+	scanFoodResponse, err := s.scanRepo.ScanFood(url)
+	if err != nil {
+		return nil, err
 	}
 
 	// Group food items by name and calculate total amount
@@ -81,82 +264,90 @@ func (s *scanFoodService) ScanFood(file *multipart.FileHeader) (*dto.ScanFoodRes
 
 	// Create response
 	response := &dto.ScanFoodResponse{
-		IsDetected: len(foodTotals) > 0,
-		FoodList:   []dto.FoodList{},
+		ScanSessionID: session.ID,
+		IsDetected:    len(foodTotals) > 0,
+		FoodList:      []dto.FoodList{},
+		Unmatched:     []dto.UnmatchedFood{},
 	}
 
-	// Match grouped foods with nutrition data
+	// Match grouped foods with the nutrition catalog
 	for name, total := range foodTotals {
-		// Find nutrition by name
-		nutrition, err := findFoodByName(foodListClientResp, name)
+		// Find nutrition by label
+		food, err := s.catalogRepo.FindFoodByLabel(name)
 		if err != nil {
-			fmt.Printf("Food not found: %s\n", name)
-			continue // Skip if food not found
+			if err.Error() != apperrors.ErrFoodNotFound().Error() {
+				return nil, err
+			}
+
+			// let the user pick the right food instead
+			response.Unmatched = append(response.Unmatched, s.unmatchedFood(name, total, session.UserID))
+			continue
 		}
 
-		// Multiply nutrition values by total
+		// Scale nutrition to a medium portion of every unit, the user can adjust the portion when saving
+		unitWeight := helper.NutritionWeight(&food.Nutrition)
+		nutrition := helper.CalculateNutrients(unitWeight*float64(total), &food.Nutrition)
 		response.FoodList = append(response.FoodList, dto.FoodList{
-			Name:         nutrition.Name,
+			Name:         food.Food.Name,
 			Unit:         total,
-			Protein:      nutrition.Protein * float64(total),
-			Sugar:        nutrition.Sugar * float64(total),
-			Carbohydrate: nutrition.Carbohydrates * float64(total),
-			Fat:          nutrition.Fat * float64(total),
+			Portion:      helper.PortionMedium,
+			Weight:       nutrition.Weight,
+			Portions:     helper.PortionOptions(unitWeight),
+			Calories:     nutrition.Calories,
+			Protein:      nutrition.Proteins,
+			Sugar:        nutrition.Sugar,
+			Carbohydrate: nutrition.Carbohydrates,
+			Fat:          nutrition.Fat,
 		})
 	}
 
+	session.RawObjects = marshalJSON(scanFoodResponse.Objects)
+	session.MatchedItems = marshalJSON(response.FoodList)
 	return response, nil
 }
 
-// Helper functions
-func findFoodByName(foods []models.ScanFood, name string) (*models.ScanFood, error) {
-	for _, food := range foods {
-		if strings.EqualFold(*food.NameIndo, name) {
-			return &food, nil
-		}
+// marshalJSON encodes a value for a JSON column, nil slices are stored as empty lists
+func marshalJSON(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil || string(data) == "null" {
+		return "[]"
+	}
+	return string(data)
+}
+
+// unmatchedFood records a label missing from the catalog and suggests foods from the food search.
+// Failures are only logged, the scan result is still useful without suggestions.
+func (s *scanFoodService) unmatchedFood(label string, unit int, userID string) dto.UnmatchedFood {
+	if err := s.scanRepo.RecordUnmatchedLabel(label); err != nil {
+		log.Printf("scan food: failed to record unmatched label %q: %v", label, err)
+	}
+
+	unmatched := dto.UnmatchedFood{
+		Label:      label,
+		Unit:       unit,
+		Candidates: []dto.FoodSearchResult{},
+	}
+
+	candidates, err := s.SearchFoodCandidates(label, maxScanCandidates, userID)
+	if err != nil {
+		log.Printf("scan food: failed to search candidates for %q: %v", label, err)
+		return unmatched
 	}
-	return nil, fmt.Errorf("food %s not found", name)
+	unmatched.Candidates = append(unmatched.Candidates, candidates...)
+	return unmatched
 }
 
 // SearchFoodByName implements ScanFoodService.
-func (s *scanFoodService) SearchFood(req *dto.FindFoodRequest) (*models.ScanFood, error) {
+// Custom foods of the user are preferred, foods unknown to the database are looked up in the USDA API.
+func (s *scanFoodService) SearchFood(req *dto.FindFoodRequest, userID string) (*models.ScanFood, error) {
 	// 1. find food by name from database where name = name and weight = weight
-	food, err := s.scanRepo.SearchFoodFromDB(req.Name)
+	food, err := s.scanRepo.SearchFoodFromDB(req.Name, userID)
 	if err != nil {
-		// 2. If food not found, call ML service to scrape food data
-		foodFromAPI, err := s.scanRepo.SearchFoodAPI(req.Name)
+		// 2. If food not found, use the USDA food picked by the user or the best ranked one
+		food, err = saveUSDAFood(s.scanRepo, req)
 		if err != nil {
 			return nil, err
 		}
-
-		// 4. Save food data to database
-		foodData := models.Food{
-			Name: foodFromAPI.Name,
-		}
-		if err := s.scanRepo.CreateFood(&foodData); err != nil {
-			return nil, err
-		}
-
-		// 5. Save food nutrition data to database
-		nutritions := models.FoodNutrition{
-			FoodID:        foodData.ID,
-			Calories:      foodFromAPI.Calories,
-			Sugar:         foodFromAPI.Sugar,
-			Fat:           foodFromAPI.Fat,
-			Carbohydrates: foodFromAPI.Carbs,
-			Proteins:      foodFromAPI.Protein,
-			Weight:        foodFromAPI.Weight,
-		}
-		if err := s.scanRepo.CreateFoodNutrition(&nutritions); err != nil {
-			return nil, err
-		}
-
-		nutritions = helper.CalculateNutrients(nutritions.Weight, &nutritions)
-		food = &models.FoodWithNutritions{
-			Food:      foodData,
-			Nutrition: nutritions,
-		}
-
 	}
 
 	// calculate nutrition data
@@ -176,25 +367,187 @@ func (s *scanFoodService) SearchFood(req *dto.FindFoodRequest) (*models.ScanFood
 	return data, nil
 }
 
+// SearchUSDAFoods implements ScanFoodService.
+// The USDA foods a food missing from the database can be created from, best ranked first.
+func (s *scanFoodService) SearchUSDAFoods(query string, limit int) ([]dto.FoodNutritionResponse, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, apperrors.ErrSearchQueryRequired()
+	}
+	if limit <= 0 || limit > maxUSDACandidates {
+		limit = maxUSDACandidates
+	}
+	return s.scanRepo.SearchFoodAPI(query, limit)
+}
+
+// findUSDAFood returns the USDA food picked by the user, or the best ranked USDA food for the name
+func findUSDAFood(scanRepo repositories.ScanFoodRepository, req *dto.FindFoodRequest) (*dto.FoodNutritionResponse, error) {
+	if req.FdcID > 0 {
+		return scanRepo.GetFoodAPI(req.FdcID)
+	}
+
+	foods, err := scanRepo.SearchFoodAPI(req.Name, maxUSDACandidates)
+	if err != nil {
+		return nil, err
+	}
+	return &foods[0], nil
+}
+
+// saveUSDAFood saves a USDA food named after the search, so the next search finds it in the database.
+// A USDA food is only saved once, picking it again returns the saved food.
+func saveUSDAFood(scanRepo repositories.ScanFoodRepository, req *dto.FindFoodRequest) (*models.FoodWithNutritions, error) {
+	foodFromAPI, err := findUSDAFood(scanRepo, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if food, err := scanRepo.GetFoodByFdcID(foodFromAPI.FdcID); err == nil {
+		return food, nil
+	}
+
+	fdcID := foodFromAPI.FdcID
+	foodData := models.Food{
+		Name:  strings.TrimSpace(req.Name),
+		FdcID: &fdcID,
+	}
+	nutritions := models.FoodNutrition{
+		Calories:      foodFromAPI.Calories,
+		Sugar:         foodFromAPI.Sugar,
+		Fat:           foodFromAPI.Fat,
+		Carbohydrates: foodFromAPI.Carbs,
+		Proteins:      foodFromAPI.Protein,
+		Weight:        foodFromAPI.Weight,
+	}
+	if err := scanRepo.CreateFoodWithNutrition(&foodData, &nutritions); err != nil {
+		// the same USDA food may have been saved by a concurrent search
+		if food, findErr := scanRepo.GetFoodByFdcID(fdcID); findErr == nil {
+			return food, nil
+		}
+		return nil, err
+	}
+
+	return &models.FoodWithNutritions{Food: foodData, Nutrition: nutritions}, nil
+}
+
+// FindFoodByBarcode implements ScanFoodService.
+// Nutrition is scaled to the requested weight, one serving of the product when not set.
+func (s *scanFoodService) FindFoodByBarcode(req *dto.BarcodeRequest) (*models.ScanFood, error) {
+	barcode, ok := helper.NormalizeBarcode(req.Barcode)
+	if !ok {
+		return nil, apperrors.ErrInvalidBarcode()
+	}
+
+	product, err := s.productRepo.FindProductByBarcode(barcode)
+	if err != nil {
+		return nil, err
+	}
+
+	// the product is cached as a food, so it can be saved with the other foods
+	food, err := s.productRepo.GetProductFood(product)
+	if err != nil {
+		return nil, err
+	}
+
+	weight := req.Weight
+	if weight <= 0 {
+		weight = product.ServingWeight
+	}
+	if weight <= 0 {
+		weight = helper.NutritionWeight(&food.Nutrition)
+	}
+
+	nutrition := helper.CalculateNutrients(weight, &food.Nutrition)
+	return &models.ScanFood{
+		Name:          food.Food.Name,
+		Calories:      nutrition.Calories,
+		Protein:       nutrition.Proteins,
+		Sugar:         nutrition.Sugar,
+		Carbohydrates: nutrition.Carbohydrates,
+		Fat:           nutrition.Fat,
+		Weight:        nutrition.Weight,
+	}, nil
+}
+
+// SearchFoodCandidates implements ScanFoodService.
+// Nutrition is returned per portion weight of the food, ranked by how well the name matches.
+func (s *scanFoodService) SearchFoodCandidates(query string, limit int, userID string) ([]dto.FoodSearchResult, error) {
+	if len([]rune(strings.TrimSpace(query))) < 2 {
+		return nil, apperrors.ErrSearchQueryRequired()
+	}
+	if limit <= 0 || limit > 50 {
+		limit = 10
+	}
+
+	foods, err := s.scanRepo.SearchFoods(query, limit, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]dto.FoodSearchResult, 0, len(foods))
+	for _, food := range foods {
+		results = append(results, dto.FoodSearchResult{
+			ID:            food.Food.ID,
+			Name:          food.Food.Name,
+			Score:         math.Round(food.Score*100) / 100,
+			Weight:        food.Nutrition.Weight,
+			Calories:      food.Nutrition.Calories,
+			Protein:       food.Nutrition.Proteins,
+			Sugar:         food.Nutrition.Sugar,
+			Carbohydrates: food.Nutrition.Carbohydrates,
+			Fat:           food.Nutrition.Fat,
+		})
+	}
+	return results, nil
+}
+
 // SaveFood implements ScanFoodService.
 func (s *scanFoodService) SaveFood(req *dto.SaveFoodRequest, userId string) error {
 	var histories []models.UserFoodHistory
 
+	meal, err := parseMeal(req.Meal)
+	if err != nil {
+		return err
+	}
+
+	// 0. Pastikan sesi scan milik user
+	var session *models.ScanSession
+	if req.ScanSessionID != nil {
+		session, err = s.scanRepo.GetScanSessionByID(*req.ScanSessionID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperrors.ErrScanSessionNotFound()
+			}
+			return err
+		}
+		if session.UserID != userId {
+			return apperrors.ErrScanSessionNotFound()
+		}
+		if session.Status == models.ScanStatusPending || session.Status == models.ScanStatusProcessing {
+			return apperrors.ErrScanInProgress()
+		}
+	}
+
 	// 1. Proses makanan hasil scan
 	if len(req.Scan) > 0 {
 		// Ambil ID makanan hasil scan
-		foodMap, err := s.scanRepo.GetFoodIDs(&req.Scan)
+		foodMap, err := s.scanRepo.GetFoodIDs(&req.Scan, userId)
 		if err != nil {
 			return err
 		}
 
 		// Buat data user food history dari hasil scan
 		for _, food := range req.Scan {
+			// Berat hanya dipakai jika diubah oleh user, selain itu dihitung dari unit
+			var weight *float64
+			if food.Weight != nil && *food.Weight > 0 {
+				weight = food.Weight
+			}
 			histories = append(histories, models.UserFoodHistory{
-				UserID: userId,
-				FoodID: foodMap[food.Name],
-				Unit:   food.Unit,
-				Weight: nil, // Berat tidak digunakan di data hasil scan
+				UserID:        userId,
+				FoodID:        foodMap[food.Name],
+				Unit:          food.Unit,
+				Weight:        weight,
+				ScanSessionID: req.ScanSessionID,
 			})
 		}
 	}
@@ -210,7 +563,7 @@ func (s *scanFoodService) SaveFood(req *dto.SaveFoodRequest, userId string) erro
 		}
 
 		// Ambil ID makanan tambahan
-		foodMap, err := s.scanRepo.GetFoodIDs(&additionalScanFoods)
+		foodMap, err := s.scanRepo.GetFoodIDs(&additionalScanFoods, userId)
 		if err != nil {
 			return err
 		}
@@ -226,7 +579,19 @@ func (s *scanFoodService) SaveFood(req *dto.SaveFoodRequest, userId string) erro
 		}
 	}
 
-	// 3. Simpan semua data user food history ke database
+	// 3. Proses resep, porsi resep dicatat dengan berat per porsinya
+	for _, serving := range req.Recipes {
+		history, err := s.recipeHistory(serving, userId)
+		if err != nil {
+			return err
+		}
+		histories = append(histories, *history)
+	}
+
+	// 4. Simpan semua data user food history ke database, semuanya dimakan pada waktu makan yang sama
+	for i := range histories {
+		histories[i].Meal = meal
+	}
 	if len(histories) > 0 {
 		if err := s.scanRepo.SaveUserFoodHistory(&histories); err != nil {
 			if strings.Contains(err.Error(), "violates foreign key constraint") {
@@ -236,5 +601,50 @@ func (s *scanFoodService) SaveFood(req *dto.SaveFoodRequest, userId string) erro
 		}
 	}
 
+	// 5. Simpan koreksi user ke sesi scan, makanan tambahan bisa jadi makanan yang tidak terdeteksi
+	if session != nil {
+		confirmedItems := append([]dto.ScanFood{}, req.Scan...)
+		for _, food := range req.Additionall {
+			weight := food.Weight
+			confirmedItems = append(confirmedItems, dto.ScanFood{Name: food.Name, Unit: 1, Weight: &weight})
+		}
+		confirmed := marshalJSON(confirmedItems)
+		now := time.Now()
+		session.ConfirmedItems = &confirmed
+		session.SavedAt = &now
+		if err := s.scanRepo.UpdateScanSession(session); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
+
+// recipeHistory creates the food history entry of servings of a recipe owned or shared with the user
+func (s *scanFoodService) recipeHistory(serving dto.RecipeServing, userID string) (*models.UserFoodHistory, error) {
+	servings := serving.Servings
+	if servings == 0 {
+		servings = 1
+	}
+	if servings < 0 {
+		return nil, apperrors.ErrInvalidRecipe()
+	}
+
+	recipe, _, err := getVisibleRecipe(s.recipeRepo, userID, serving.RecipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	nutrition, err := s.recipeRepo.GetFoodNutrition(recipe.FoodID)
+	if err != nil {
+		return nil, err
+	}
+
+	weight := helper.NutritionWeight(nutrition) * servings
+	return &models.UserFoodHistory{
+		UserID: userID,
+		FoodID: recipe.FoodID,
+		Unit:   1,
+		Weight: &weight,
+	}, nil
+}
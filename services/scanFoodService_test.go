@@ -0,0 +1,268 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/jpeg"
+	"io"
+	"mime/multipart"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/dto"
+	apperrors "github.com/rizkirmdhnnn/sweetlife-backend-go/errors"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/models"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/repositories"
+	"github.com/rizkirmdhnnn/sweetlife-backend-go/worker"
+	"gorm.io/gorm"
+)
+
+// fakeScanRepo keeps scan sessions in memory and detects the foods in objects
+type fakeScanRepo struct {
+	repositories.ScanFoodRepository
+	mu       sync.Mutex
+	sessions map[uint]models.ScanSession
+	objects  []dto.ScanFood
+	scanErr  error
+}
+
+func (r *fakeScanRepo) ScanFood(image string) (*dto.ScanFoodClientResp, error) {
+	if r.scanErr != nil {
+		return nil, r.scanErr
+	}
+	return &dto.ScanFoodClientResp{Objects: r.objects}, nil
+}
+
+func (r *fakeScanRepo) CreateScanSession(session *models.ScanSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session.ID = uint(len(r.sessions) + 1)
+	session.UpdatedAt = time.Now()
+	r.sessions[session.ID] = *session
+	return nil
+}
+
+func (r *fakeScanRepo) UpdateScanSession(session *models.ScanSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session.UpdatedAt = time.Now()
+	r.sessions[session.ID] = *session
+	return nil
+}
+
+func (r *fakeScanRepo) GetScanSessionByID(id uint) (*models.ScanSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session, found := r.sessions[id]
+	if !found {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &session, nil
+}
+
+type fakeStorageRepo struct {
+	repositories.StorageBucketRepository
+}
+
+func (fakeStorageRepo) UploadFile(ctx context.Context, bucketName, objectName string, file io.Reader) (string, error) {
+	return "https://storage.example.com/" + objectName, nil
+}
+
+// fakeCatalogRepo knows every label with 100 kcal per 100 g
+type fakeCatalogRepo struct{}
+
+func (fakeCatalogRepo) FindFoodByLabel(label string) (*models.FoodWithNutritions, error) {
+	return &models.FoodWithNutritions{
+		Food:      models.Food{Name: label},
+		Nutrition: models.FoodNutrition{Calories: 100, Weight: 100},
+	}, nil
+}
+
+type fakeProductRepo struct {
+	repositories.ProductRepository
+}
+
+type fakeRecipeRepo struct {
+	repositories.RecipeRepository
+}
+
+func newScanTestService(repo *fakeScanRepo, pool *worker.Pool) ScanFoodService {
+	return NewScanFoodService(repo, fakeStorageRepo{}, fakeCatalogRepo{}, fakeProductRepo{}, fakeRecipeRepo{}, pool)
+}
+
+// newScanImage returns an uploaded jpeg image
+func newScanImage(t *testing.T) *multipart.FileHeader {
+	t.Helper()
+	var img bytes.Buffer
+	if err := jpeg.Encode(&img, image.NewRGBA(image.Rect(0, 0, 16, 16)), nil); err != nil {
+		t.Fatalf("encode jpeg: %v", err)
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("image", "food.jpg")
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	_, _ = part.Write(img.Bytes())
+	_ = writer.Close()
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatalf("read form: %v", err)
+	}
+	return form.File["image"][0]
+}
+
+func TestScanFoodAsync(t *testing.T) {
+	repo := &fakeScanRepo{sessions: make(map[uint]models.ScanSession), objects: []dto.ScanFood{{Name: "Nasi", Unit: 1}, {Name: "Tempe", Unit: 2}}}
+	pool := worker.NewPool(1, 1)
+	service := newScanTestService(repo, pool)
+
+	job, err := service.ScanFoodAsync(newScanImage(t), "user")
+	if err != nil {
+		t.Fatalf("ScanFoodAsync() error = %v", err)
+	}
+	if job.ScanSessionID == 0 || job.Result != nil {
+		t.Errorf("ScanFoodAsync() = %+v, want a queued scan without a result", job)
+	}
+
+	// Close waits for the queued scan
+	pool.Close()
+
+	got, err := service.GetScan(job.ScanSessionID, "user")
+	if err != nil {
+		t.Fatalf("GetScan() error = %v", err)
+	}
+	if got.Status != string(models.ScanStatusDone) || got.Result == nil || len(got.Result.FoodList) != 2 {
+		t.Fatalf("GetScan() = %+v, want a done scan with 2 foods", got)
+	}
+	if got.Result.ScanSessionID != job.ScanSessionID {
+		t.Errorf("result scan session = %d, want %d", got.Result.ScanSessionID, job.ScanSessionID)
+	}
+
+	if _, err := service.GetScan(job.ScanSessionID, "other"); err == nil || err.Error() != apperrors.ErrScanSessionNotFound().Error() {
+		t.Errorf("GetScan() by another user error = %v, want %v", err, apperrors.ErrScanSessionNotFound())
+	}
+}
+
+func TestScanFoodAsyncQueueFull(t *testing.T) {
+	repo := &fakeScanRepo{sessions: make(map[uint]models.ScanSession)}
+	pool := worker.NewPool(1, 0)
+	service := newScanTestService(repo, pool)
+
+	// keep the only worker busy, without a queue the next scan is rejected
+	release := make(chan struct{})
+	for !pool.Submit(func() { <-release }) {
+		time.Sleep(time.Millisecond)
+	}
+	defer func() {
+		close(release)
+		pool.Close()
+	}()
+
+	_, err := service.ScanFoodAsync(newScanImage(t), "user")
+	if err == nil || err.Error() != apperrors.ErrScanQueueFull().Error() {
+		t.Fatalf("ScanFoodAsync() error = %v, want %v", err, apperrors.ErrScanQueueFull())
+	}
+
+	session := repo.sessions[1]
+	if session.Status != models.ScanStatusFailed || session.Error != apperrors.ErrScanQueueFull().Error() {
+		t.Errorf("scan session = %+v, want failed because the queue is full", session)
+	}
+}
+
+func TestScanFoodAsyncHidesInternalErrors(t *testing.T) {
+	repo := &fakeScanRepo{sessions: make(map[uint]models.ScanSession), scanErr: errors.New("dial tcp 10.0.0.7:5000: connection refused")}
+	pool := worker.NewPool(1, 1)
+	service := newScanTestService(repo, pool)
+
+	job, err := service.ScanFoodAsync(newScanImage(t), "user")
+	if err != nil {
+		t.Fatalf("ScanFoodAsync() error = %v", err)
+	}
+	pool.Close()
+
+	got, err := service.GetScan(job.ScanSessionID, "user")
+	if err != nil {
+		t.Fatalf("GetScan() error = %v", err)
+	}
+	if got.Status != string(models.ScanStatusFailed) || got.Error != apperrors.ErrScanFailed().Error() {
+		t.Errorf("GetScan() = %+v, want a failed scan with a generic error", got)
+	}
+}
+
+func TestGetScanFailsStaleScans(t *testing.T) {
+	repo := &fakeScanRepo{sessions: map[uint]models.ScanSession{
+		1: {ID: 1, UserID: "user", Status: models.ScanStatusPending, UpdatedAt: time.Now().Add(-2 * scanStaleAfter)},
+	}}
+	pool := worker.NewPool(1, 1)
+	defer pool.Close()
+	service := newScanTestService(repo, pool)
+
+	got, err := service.GetScan(1, "user")
+	if err != nil {
+		t.Fatalf("GetScan() error = %v", err)
+	}
+	if got.Status != string(models.ScanStatusFailed) {
+		t.Errorf("GetScan() status = %s, want failed for a scan lost with the queue", got.Status)
+	}
+}
+
+// fakeUSDARepo answers USDA searches with one food and keeps the foods created from it
+type fakeUSDARepo struct {
+	repositories.ScanFoodRepository
+	usda    dto.FoodNutritionResponse
+	limit   int
+	created []models.FoodWithNutritions
+}
+
+func (r *fakeUSDARepo) SearchFoodAPI(query string, limit int) ([]dto.FoodNutritionResponse, error) {
+	r.limit = limit
+	return []dto.FoodNutritionResponse{r.usda}, nil
+}
+
+func (r *fakeUSDARepo) GetFoodByFdcID(fdcID int) (*models.FoodWithNutritions, error) {
+	for _, food := range r.created {
+		if food.Food.FdcID != nil && *food.Food.FdcID == fdcID {
+			return &food, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUSDARepo) CreateFoodWithNutrition(food *models.Food, nutrition *models.FoodNutrition) error {
+	food.ID = uint(len(r.created) + 1)
+	r.created = append(r.created, models.FoodWithNutritions{Food: *food, Nutrition: *nutrition})
+	return nil
+}
+
+func TestSaveUSDAFoodSavesAUSDAFoodOnce(t *testing.T) {
+	repo := &fakeUSDARepo{usda: dto.FoodNutritionResponse{FdcID: 42, Name: "Chicken, grilled", Calories: 165, Protein: 31, Weight: 100}}
+
+	first, err := saveUSDAFood(repo, &dto.FindFoodRequest{Name: " Ayam Bakar "})
+	if err != nil {
+		t.Fatalf("saveUSDAFood() error = %v", err)
+	}
+	if first.Food.Name != "Ayam Bakar" || first.Food.FdcID == nil || *first.Food.FdcID != 42 {
+		t.Errorf("saved food = %+v, want Ayam Bakar from USDA food 42", first.Food)
+	}
+	if first.Nutrition.Calories != 165 || first.Nutrition.Proteins != 31 || first.Nutrition.Weight != 100 {
+		t.Errorf("saved nutrition = %+v, want the USDA nutrition", first.Nutrition)
+	}
+	if repo.limit != maxUSDACandidates {
+		t.Errorf("USDA search limit = %d, want %d", repo.limit, maxUSDACandidates)
+	}
+
+	// another name for the same USDA food returns the saved food
+	second, err := saveUSDAFood(repo, &dto.FindFoodRequest{Name: "Grilled chicken"})
+	if err != nil {
+		t.Fatalf("saveUSDAFood() error = %v", err)
+	}
+	if second.Food.ID != first.Food.ID || len(repo.created) != 1 {
+		t.Errorf("USDA food 42 saved %d times, want once", len(repo.created))
+	}
+}
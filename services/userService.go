@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"mime/multipart"
 	"net/url"
 	"path/filepath"
@@ -23,20 +24,21 @@ type UserService interface {
 
 	// Profile
 	GetProfile(id string) (*dto.UserResponse, error)
-	GetFoodHistoryWithPagination(userID string) (*dto.FoodHistoryResponse, error)
+	GetFoodHistoryWithPagination(userID, lang string) (*dto.FoodHistoryResponse, error)
 
 	// GetDashboard
 	GetDashboard(userID string) (*dto.DailyProgressResponse, error)
 }
 
 type userService struct {
-	userRepo    repositories.UserRepository
-	authRepo    repositories.AuthRepository
-	storageRepo repositories.StorageBucketRepository
-	healthRepo  repositories.HealthProfileRepository
+	userRepo     repositories.UserRepository
+	authRepo     repositories.AuthRepository
+	storageRepo  repositories.StorageBucketRepository
+	healthRepo   repositories.HealthProfileRepository
+	activityRepo repositories.ActivityRepository
 }
 
-func NewUserService(userRepo repositories.UserRepository, authRepo repositories.AuthRepository, storageRepo repositories.StorageBucketRepository, healthRepo repositories.HealthProfileRepository) UserService {
+func NewUserService(userRepo repositories.UserRepository, authRepo repositories.AuthRepository, storageRepo repositories.StorageBucketRepository, healthRepo repositories.HealthProfileRepository, activityRepo repositories.ActivityRepository) UserService {
 	if userRepo == nil {
 		panic("userRepo cannot be nil")
 	}
@@ -50,12 +52,16 @@ func NewUserService(userRepo repositories.UserRepository, authRepo repositories.
 	if healthRepo == nil {
 		panic("healthRepo cannot be nil")
 	}
+	if activityRepo == nil {
+		panic("activityRepo cannot be nil")
+	}
 
 	return &userService{
-		userRepo:    userRepo,
-		authRepo:    authRepo,
-		storageRepo: storageRepo,
-		healthRepo:  healthRepo,
+		userRepo:     userRepo,
+		authRepo:     authRepo,
+		storageRepo:  storageRepo,
+		healthRepo:   healthRepo,
+		activityRepo: activityRepo,
 	}
 }
 
@@ -212,9 +218,9 @@ func (u *userService) GetProfile(id string) (*dto.UserResponse, error) {
 
 // TODO: ini untuk makanan yang dimasukin manual total kalorinya belum bener
 // harusnya total kalorinya berdasarkan weightnya, klo yang sekarang masih berdasarkan total unit yang default weightnya 100
-func (s *userService) GetFoodHistoryWithPagination(userID string) (*dto.FoodHistoryResponse, error) {
+func (s *userService) GetFoodHistoryWithPagination(userID, lang string) (*dto.FoodHistoryResponse, error) {
 	// Get food history
-	foodHistory, err := s.userRepo.GetFoodHistory(userID)
+	foodHistory, err := s.userRepo.GetFoodHistory(userID, lang)
 	if err != nil {
 		return nil, err
 	}
@@ -313,6 +319,12 @@ func (u *userService) GetDashboard(userID string) (*dto.DailyProgressResponse, e
 		return nil, err
 	}
 
+	// Get calories burned by today's activities
+	caloriesBurned, err := u.activityRepo.GetCaloriesBurnedByDate(userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
 	// Dalam fungsi GetDashboard
 	caloriesSatisfication := helper.DetermineSatisfication(
 		float64(dailyNutrition.TotalCalories),
@@ -369,6 +381,11 @@ func (u *userService) GetDashboard(userID string) (*dto.DailyProgressResponse, e
 			),
 		},
 		User: userResp,
+		CaloriesBalance: dto.DailyCaloriesBalance{
+			Intake:      dailyNutrition.TotalCalories,
+			Burned:      caloriesBurned,
+			NetCalories: math.Round((dailyNutrition.TotalCalories-caloriesBurned)*100) / 100,
+		},
 	}
 
 	return dailyProgress, nil
@@ -0,0 +1,70 @@
+// Package worker runs background jobs on a fixed number of goroutines, so the
+// load a burst of requests puts on a slow upstream stays bounded.
+package worker
+
+import (
+	"log"
+	"sync"
+)
+
+// Job is a unit of background work.
+type Job func()
+
+// Pool runs jobs from a bounded queue on a fixed number of workers.
+type Pool struct {
+	jobs chan Job
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+// NewPool starts a pool with the given number of workers and queue size.
+func NewPool(workers, queueSize int) *Pool {
+	if workers <= 0 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+
+	p := &Pool{jobs: make(chan Job, queueSize)}
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.run()
+	}
+	return p
+}
+
+// Submit queues a job without blocking, it reports false when the queue is full.
+func (p *Pool) Submit(job Job) bool {
+	select {
+	case p.jobs <- job:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close stops accepting jobs and waits for the queued jobs to finish.
+func (p *Pool) Close() {
+	p.once.Do(func() {
+		close(p.jobs)
+	})
+	p.wg.Wait()
+}
+
+func (p *Pool) run() {
+	defer p.wg.Done()
+	for job := range p.jobs {
+		p.execute(job)
+	}
+}
+
+// execute runs a job, a panicking job must not take the worker down with it.
+func (p *Pool) execute(job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("worker: job panicked: %v", r)
+		}
+	}()
+	job()
+}
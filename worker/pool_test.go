@@ -0,0 +1,63 @@
+package worker
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func TestPoolRunsJobs(t *testing.T) {
+	p := NewPool(3, 10)
+
+	var done atomic.Int32
+	for i := 0; i < 10; i++ {
+		if !p.Submit(func() { done.Add(1) }) {
+			t.Fatalf("Submit() of job %d = false, want true", i+1)
+		}
+	}
+	p.Close()
+
+	if got := done.Load(); got != 10 {
+		t.Errorf("jobs done = %d, want 10 after Close", got)
+	}
+}
+
+func TestPoolSubmitFullQueue(t *testing.T) {
+	p := NewPool(1, 1)
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	// the worker is busy with the first job and the second fills the queue
+	p.Submit(func() {
+		close(started)
+		<-release
+	})
+	<-started
+	if !p.Submit(func() {}) {
+		t.Fatal("Submit() = false, want the job queued")
+	}
+	if p.Submit(func() {}) {
+		t.Error("Submit() = true, want false with a full queue")
+	}
+
+	close(release)
+	p.Close()
+}
+
+func TestPoolSurvivesPanic(t *testing.T) {
+	p := NewPool(1, 2)
+
+	var done atomic.Bool
+	p.Submit(func() { panic("boom") })
+	p.Submit(func() { done.Store(true) })
+	p.Close()
+
+	if !done.Load() {
+		t.Error("the job after a panicking job did not run")
+	}
+}
+
+func TestPoolCloseTwice(t *testing.T) {
+	p := NewPool(2, 1)
+	p.Close()
+	p.Close()
+}